@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
 	"os/signal"
 	"syscall"
 
@@ -17,6 +18,13 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	if flag.Arg(0) == "deadletter" {
+		if err := app.DeadLetter(ctx, *configPath, flag.Args()[1:], os.Stdout); err != nil {
+			log.Fatalf("deadletter: %v", err)
+		}
+		return
+	}
+
 	if err := app.Run(ctx, *configPath); err != nil {
 		log.Fatalf("run app: %v", err)
 	}
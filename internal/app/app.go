@@ -6,24 +6,32 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"sync"
 	"time"
 
-	"github.com/robfig/cron/v3"
-
 	"services-health-check/internal/checkers/cloudflare"
 	"services-health-check/internal/checkers/domain"
 	httpcheck "services-health-check/internal/checkers/http"
 	"services-health-check/internal/checkers/k8s"
 	"services-health-check/internal/checkers/ssl"
 	"services-health-check/internal/config"
+	"services-health-check/internal/core/aggregate"
 	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/i18n"
 	"services-health-check/internal/core/notify"
+	"services-health-check/internal/core/outbox"
 	"services-health-check/internal/core/policy"
+	"services-health-check/internal/core/route"
+	"services-health-check/internal/core/scheduler"
+	"services-health-check/internal/core/silence"
 	"services-health-check/internal/notifiers/discord"
+	"services-health-check/internal/notifiers/format"
 	"services-health-check/internal/notifiers/gchat"
+	"services-health-check/internal/notifiers/line"
+	"services-health-check/internal/notifiers/pagerduty"
 	"services-health-check/internal/notifiers/slack"
 	"services-health-check/internal/notifiers/smtp"
+	"services-health-check/internal/notifiers/teams"
+	"services-health-check/internal/notifiers/telegram"
 	"services-health-check/internal/notifiers/webhook"
 	"services-health-check/internal/utils/logger"
 )
@@ -33,6 +41,7 @@ type scheduledCheck struct {
 	Interval   time.Duration
 	Schedule   string
 	Type       string
+	Labels     map[string]string
 	StopOnFail bool
 	RunOnce    bool
 }
@@ -51,6 +60,9 @@ func Run(ctx context.Context, configPath string) error {
 		defer closeLog()
 	}
 	log.Infof("config loaded: %s", configPath)
+	if err := i18n.SetDefault(cfg.Locale); err != nil {
+		return err
+	}
 
 	checks, err := buildChecks(cfg)
 	if err != nil {
@@ -68,45 +80,111 @@ func Run(ctx context.Context, configPath string) error {
 	}
 	log.Infof("notifiers ready: %d", len(notifiers))
 
-	pol := buildPolicy(cfg)
+	silences, err := buildSilencer(cfg)
+	if err != nil {
+		return fmt.Errorf("build silences: %w", err)
+	}
+	routes, err := buildRoutes(cfg.Routes)
+	if err != nil {
+		return fmt.Errorf("build routes: %w", err)
+	}
+	d := &dispatcher{cfg: cfg, notifiers: notifiers, locales: channelLocales(cfg), routes: routes, silences: silences, log: log, perCheck: perCheckChannels(cfg)}
+
+	withRetry(cfg, notifiers, log)
+	d.outbox = buildOutbox(cfg, notifiers, log)
+	withDeliveryMarks(d.outbox, notifiers, log)
+	d.withBreakers(ctx)
+	queues := startQueues(cfg, notifiers, d.outbox, log)
+	defer drainQueues(cfg.Notify.DrainTimeout, queues, log)
+	go runQueueStats(ctx, queues, log)
+	if d.outbox != nil {
+		log.Infof("outbox ready: %s (dead letter: %s)", cfg.Notify.OutboxFile, deadLetterPath(cfg))
+		go runOutbox(ctx, d.outbox, cfg.Notify.OutboxInterval, log)
+	}
 
-	results := make(chan check.Result)
-	var wg sync.WaitGroup
+	pol := buildPolicy(cfg, log)
 
+	sched := scheduler.NewPool(cfg.Scheduler.Workers)
+	results := make(chan check.Result, sched.Workers)
 	for _, sc := range checks {
-		wg.Add(1)
-		go func(sc scheduledCheck) {
-			defer wg.Done()
-			runCheckLoop(ctx, sc, results, log)
-		}(sc)
+		if err := sched.Add(newJob(sc, results)); err != nil {
+			log.Errorf("invalid schedule for %q: %v", sc.Checker.Name(), err)
+		}
+	}
+	if err := sched.Start(ctx); err != nil {
+		return fmt.Errorf("start scheduler: %w", err)
 	}
+	log.Infof("scheduler ready: workers=%d", sched.Workers)
 
 	go func() {
-		wg.Wait()
+		select {
+		case <-ctx.Done():
+		case <-sched.Done():
+		}
+		_ = sched.Stop(context.Background())
 		close(results)
 	}()
 
+	statuses, _ := pol.(policy.StatusReader)
+	esc := newEscalator(cfg.Routes, routes, statuses)
+	go esc.run(ctx, d)
+
 	var agg chan notify.Event
+	stopAgg := make(chan struct{})
+	aggDone := make(chan struct{})
 	if cfg.Notify.AggregateByType {
 		agg = make(chan notify.Event, 100)
-		expected := countChecksByType(cfg)
-		go runAggregator(ctx, cfg, agg, notifiers, log, expected)
+		go func() {
+			defer close(aggDone)
+			runAggregator(ctx, stopAgg, d, agg, buildGrouper(cfg))
+		}()
+	} else {
+		close(aggDone)
 	}
 
+	deliver := func(event notify.Event) {
+		esc.track(event)
+		if agg != nil {
+			if len(d.perCheck) > 0 {
+				d.dispatchTo(ctx, event, func(name string) bool { return d.perCheck[name] })
+			}
+			select {
+			case agg <- event:
+			case <-ctx.Done():
+			}
+			return
+		}
+		d.dispatch(ctx, event)
+	}
+	held := silence.NewHeld()
+	go runHeld(ctx, d, held, deliver)
+
 	for res := range results {
 		logResult(log, res)
 		event, err := pol.Evaluate(ctx, res)
-		if err != nil || event == nil {
+		if err != nil {
+			log.Errorf("policy %s: %v", res.Name, err)
+		}
+		if event == nil {
 			continue
 		}
-		event.Type = res.Type
-		if agg != nil {
-			agg <- *event
+		if event.Type == "" {
+			event.Type = res.Type
+		}
+		if d.silenced(*event) {
+			held.Add(*event)
 			continue
 		}
-		dispatch(ctx, cfg, *event, notifiers, log)
+		if held.Sent(*event) {
+			log.Infof("drop %s %s: failure was silenced and never announced", event.Service, event.Status)
+			continue
+		}
+		deliver(*event)
 	}
 
+	// Flush the aggregates before the deferred drain closes the queues.
+	close(stopAgg)
+	<-aggDone
 	return nil
 }
 
@@ -151,6 +229,7 @@ func buildChecks(cfg *config.Config) ([]scheduledCheck, error) {
 				Interval:   c.Interval,
 				Schedule:   c.Schedule,
 				Type:       c.Type,
+				Labels:     c.Labels,
 				StopOnFail: cfg.Notify.StopOnFail,
 				RunOnce:    cfg.Notify.RunOnce,
 			})
@@ -168,6 +247,7 @@ func buildChecks(cfg *config.Config) ([]scheduledCheck, error) {
 				Interval:   c.Interval,
 				Schedule:   c.Schedule,
 				Type:       c.Type,
+				Labels:     c.Labels,
 				StopOnFail: cfg.Notify.StopOnFail,
 				RunOnce:    cfg.Notify.RunOnce,
 			})
@@ -189,6 +269,7 @@ func buildChecks(cfg *config.Config) ([]scheduledCheck, error) {
 				Interval:   c.Interval,
 				Schedule:   c.Schedule,
 				Type:       c.Type,
+				Labels:     c.Labels,
 				StopOnFail: cfg.Notify.StopOnFail,
 				RunOnce:    cfg.Notify.RunOnce,
 			})
@@ -206,6 +287,7 @@ func buildChecks(cfg *config.Config) ([]scheduledCheck, error) {
 				Interval:   c.Interval,
 				Schedule:   c.Schedule,
 				Type:       c.Type,
+				Labels:     c.Labels,
 				StopOnFail: cfg.Notify.StopOnFail,
 				RunOnce:    cfg.Notify.RunOnce,
 			})
@@ -227,6 +309,7 @@ func buildChecks(cfg *config.Config) ([]scheduledCheck, error) {
 				Interval:   c.Interval,
 				Schedule:   c.Schedule,
 				Type:       c.Type,
+				Labels:     c.Labels,
 				StopOnFail: cfg.Notify.StopOnFail,
 				RunOnce:    cfg.Notify.RunOnce,
 			})
@@ -240,6 +323,10 @@ func buildChecks(cfg *config.Config) ([]scheduledCheck, error) {
 func buildNotifiers(cfg *config.Config) (map[string]notify.Notifier, error) {
 	notifiers := make(map[string]notify.Notifier)
 	for i, c := range cfg.Channels {
+		tmpls, err := format.ParseTemplates(c.Name, c.TitleTemplate, c.BodyTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("channel %s %w", c.Name, err)
+		}
 		switch c.Type {
 		case "webhook":
 			timeout := c.Timeout
@@ -247,9 +334,14 @@ func buildNotifiers(cfg *config.Config) (map[string]notify.Notifier, error) {
 				timeout = 5 * time.Second
 			}
 			notifiers[c.Name] = &webhook.Notifier{
-				NameValue: c.Name,
-				URL:       c.URL,
-				Timeout:   timeout,
+				NameValue:  c.Name,
+				URL:        c.URL,
+				Method:     c.Method,
+				Headers:    c.Headers,
+				Template:   tmpls.Body,
+				HMACSecret: c.HMACSecret,
+				HMACHeader: c.HMACHeader,
+				Timeout:    timeout,
 			}
 		case "discord":
 			timeout := c.Timeout
@@ -260,6 +352,7 @@ func buildNotifiers(cfg *config.Config) (map[string]notify.Notifier, error) {
 				NameValue: c.Name,
 				URL:       c.URL,
 				Username:  c.Username,
+				Templates: tmpls,
 				Timeout:   timeout,
 			}
 		case "slack":
@@ -270,6 +363,7 @@ func buildNotifiers(cfg *config.Config) (map[string]notify.Notifier, error) {
 			notifiers[c.Name] = &slack.Notifier{
 				NameValue: c.Name,
 				URL:       c.URL,
+				Templates: tmpls,
 				Timeout:   timeout,
 			}
 		case "gchat":
@@ -280,6 +374,7 @@ func buildNotifiers(cfg *config.Config) (map[string]notify.Notifier, error) {
 			notifiers[c.Name] = &gchat.Notifier{
 				NameValue: c.Name,
 				URL:       c.URL,
+				Templates: tmpls,
 				Timeout:   timeout,
 			}
 		case "smtp":
@@ -299,6 +394,57 @@ func buildNotifiers(cfg *config.Config) (map[string]notify.Notifier, error) {
 				Timeout:       timeout,
 				ImplicitTLS:   c.SMTPImplicitTLS,
 				SkipVerifyTLS: c.SMTPSkipVerifyTLS,
+				Templates:     tmpls,
+			}
+		case "teams":
+			timeout := c.Timeout
+			if timeout == 0 {
+				timeout = 5 * time.Second
+			}
+			notifiers[c.Name] = &teams.Notifier{
+				NameValue: c.Name,
+				URL:       c.URL,
+				Templates: tmpls,
+				Timeout:   timeout,
+			}
+		case "telegram":
+			timeout := c.Timeout
+			if timeout == 0 {
+				timeout = 5 * time.Second
+			}
+			notifiers[c.Name] = &telegram.Notifier{
+				NameValue: c.Name,
+				URL:       c.URL,
+				BotToken:  c.TelegramBotToken,
+				ChatID:    c.TelegramChatID,
+				ParseMode: c.TelegramParseMode,
+				Templates: tmpls,
+				Timeout:   timeout,
+			}
+		case "line":
+			timeout := c.Timeout
+			if timeout == 0 {
+				timeout = 5 * time.Second
+			}
+			notifiers[c.Name] = &line.Notifier{
+				NameValue: c.Name,
+				URL:       c.URL,
+				Token:     c.LineToken,
+				To:        c.LineTo,
+				Flex:      c.LineFlex,
+				Templates: tmpls,
+				Timeout:   timeout,
+			}
+		case "pagerduty":
+			timeout := c.Timeout
+			if timeout == 0 {
+				timeout = 5 * time.Second
+			}
+			notifiers[c.Name] = &pagerduty.Notifier{
+				NameValue:  c.Name,
+				URL:        c.URL,
+				RoutingKey: c.PagerDutyKey,
+				Timeout:    timeout,
 			}
 		default:
 			return nil, fmt.Errorf("unknown channel type at index %d (name=%q): %q", i, c.Name, c.Type)
@@ -307,73 +453,77 @@ func buildNotifiers(cfg *config.Config) (map[string]notify.Notifier, error) {
 	return notifiers, nil
 }
 
-func buildPolicy(cfg *config.Config) *policy.SimplePolicy {
-	var polCfg config.PolicyConfig
-	if len(cfg.Policies) > 0 {
-		polCfg = cfg.Policies[0]
-	}
-	return policy.NewSimplePolicy(polCfg.Cooldown, polCfg.NotifyOnRecovery)
-}
-
-func runCheckLoop(ctx context.Context, sc scheduledCheck, results chan<- check.Result, log *logger.Logger) {
-	status := runOnce(ctx, sc.Checker, results, sc.Type)
-	if sc.RunOnce {
-		return
-	}
-	if sc.StopOnFail && status != check.StatusOK {
-		return
+func buildPolicy(cfg *config.Config, log *logger.Logger) policy.Policy {
+	var store policy.StateStore
+	if cfg.State.File != "" {
+		store = policy.NewFileStore(cfg.State.File)
 	}
 
-	if sc.Schedule != "" {
-		runCronLoop(ctx, sc, results, log)
-		return
+	named := make(map[string]policy.Policy)
+	for _, polCfg := range cfg.Policies {
+		if polCfg.Name != "" {
+			named[polCfg.Name] = newSimplePolicy(polCfg, store, log)
+		}
 	}
 
-	interval := sc.Interval
-	if interval == 0 {
-		return
+	defCfg, _ := cfg.DefaultPolicy()
+	def, ok := named[defCfg.Name]
+	if !ok {
+		def = newSimplePolicy(defCfg, store, log)
 	}
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			status = runOnce(ctx, sc.Checker, results, sc.Type)
-			if sc.StopOnFail && status != check.StatusOK {
-				return
-			}
+	reg := policy.NewRegistry(def)
+	parents := make(map[string][]string)
+	for _, c := range cfg.Checks {
+		if p, ok := named[c.Policy]; ok {
+			reg.Bind(c.Name, p)
+		}
+		if len(c.DependsOn) > 0 {
+			parents[c.Name] = c.DependsOn
 		}
 	}
+	if len(parents) == 0 {
+		return reg
+	}
+	return policy.NewDependencyPolicy(reg, parents)
 }
 
-func runCronLoop(ctx context.Context, sc scheduledCheck, results chan<- check.Result, log *logger.Logger) {
-	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
-	c := cron.New(cron.WithParser(parser))
-	_, err := c.AddFunc(sc.Schedule, func() {
-		status := runOnce(ctx, sc.Checker, results, sc.Type)
-		if sc.StopOnFail && status != check.StatusOK {
-			return
-		}
-	})
-	if err != nil {
-		log.Errorf("invalid schedule for %q: %v", sc.Checker.Name(), err)
-		return
+func newSimplePolicy(polCfg config.PolicyConfig, store policy.StateStore, log *logger.Logger) *policy.SimplePolicy {
+	pol := policy.NewSimplePolicy(polCfg.Cooldown, polCfg.NotifyOnRecovery)
+	pol.FailureThreshold = polCfg.FailureThreshold
+	pol.SuccessThreshold = polCfg.SuccessThreshold
+	pol.RepeatInterval = polCfg.RepeatInterval
+	pol.MaxRepeats = polCfg.MaxRepeats
+	if polCfg.FlapWindow > 0 {
+		pol.Flap = policy.NewFlapDetector(polCfg.FlapWindow, polCfg.FlapHigh, polCfg.FlapLow)
+	}
+	pol.Store = store
+	if err := pol.Restore(); err != nil {
+		log.Warnf("restore policy state %q: %v", polCfg.Name, err)
 	}
-	c.Start()
-	defer c.Stop()
+	return pol
+}
 
-	<-ctx.Done()
+func newJob(sc scheduledCheck, results chan<- check.Result) scheduler.Job {
+	return scheduler.Job{
+		Name:     sc.Checker.Name(),
+		Interval: sc.Interval,
+		Schedule: sc.Schedule,
+		RunOnce:  sc.RunOnce,
+		Run: func(ctx context.Context) bool {
+			status := runOnce(ctx, sc.Checker, results, sc.Type, sc.Labels)
+			return !(sc.StopOnFail && status != check.StatusOK)
+		},
+	}
 }
 
-func runOnce(ctx context.Context, checker check.Checker, results chan<- check.Result, checkType string) check.Status {
+func runOnce(ctx context.Context, checker check.Checker, results chan<- check.Result, checkType string, labels map[string]string) check.Status {
 	if ctx.Err() != nil {
 		return check.StatusUnknown
 	}
 	res, err := checker.Check(ctx)
 	res.Type = checkType
+	res.Labels = labels
 	if err != nil {
 		if ctx.Err() == nil {
 			results <- res
@@ -386,8 +536,10 @@ func runOnce(ctx context.Context, checker check.Checker, results chan<- check.Re
 	return res.Status
 }
 
-func runAggregator(ctx context.Context, cfg *config.Config, in <-chan notify.Event, notifiers map[string]notify.Notifier, log *logger.Logger, expected map[string]int) {
-	window := cfg.Notify.AggregateWindow
+// runAggregator groups events until ctx ends or stop is closed, then sends
+// what it still holds with a fresh context, as ctx may already be cancelled.
+func runAggregator(ctx context.Context, stop <-chan struct{}, d *dispatcher, in <-chan notify.Event, groups *aggregate.Grouper) {
+	window := d.cfg.Notify.AggregateWindow
 	if window == 0 {
 		window = 30 * time.Second
 	}
@@ -395,57 +547,86 @@ func runAggregator(ctx context.Context, cfg *config.Config, in <-chan notify.Eve
 	defer ticker.Stop()
 
 	buffer := make(map[string][]notify.Event)
-	flushAll := func() {
+	add := func(ctx context.Context, ev notify.Event) {
+		key := groups.Key(ev.Type, ev.Labels)
+		buffer[key] = append(buffer[key], ev)
+		if expected := groups.Expected(key); expected > 0 && len(buffer[key]) >= expected {
+			aggregateAndDispatch(ctx, d, key, buffer[key])
+			delete(buffer, key)
+		}
+	}
+	flushAll := func(ctx context.Context) {
 		for key, items := range buffer {
 			if len(items) == 0 {
 				continue
 			}
-			aggregateAndDispatch(ctx, cfg, key, items, notifiers, log)
+			aggregateAndDispatch(ctx, d, key, items)
 		}
 		buffer = make(map[string][]notify.Event)
 	}
-	flushType := func(key string) {
-		items := buffer[key]
-		if len(items) == 0 {
-			return
+	shutdown := func() {
+		timeout := d.cfg.Notify.DrainTimeout
+		if timeout <= 0 {
+			timeout = defaultDrainTimeout
+		}
+		flushCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		for {
+			select {
+			case ev := <-in:
+				add(flushCtx, ev)
+			default:
+				flushAll(flushCtx)
+				return
+			}
 		}
-		aggregateAndDispatch(ctx, cfg, key, items, notifiers, log)
-		delete(buffer, key)
 	}
 
 	for {
 		select {
 		case <-ctx.Done():
-			flushAll()
+			shutdown()
+			return
+		case <-stop:
+			shutdown()
 			return
 		case ev := <-in:
-			key := ev.Type
-			if key == "" {
-				key = "unknown"
-			}
-			buffer[key] = append(buffer[key], ev)
-			if expected[key] > 0 && len(buffer[key]) >= expected[key] {
-				flushType(key)
-			}
+			add(ctx, ev)
 		case <-ticker.C:
-			flushAll()
+			flushAll(ctx)
 		}
 	}
 }
 
-func aggregateAndDispatch(ctx context.Context, cfg *config.Config, key string, items []notify.Event, notifiers map[string]notify.Notifier, log *logger.Logger) {
+// aggregateAndDispatch sends one event for the group key. The aggregate
+// carries the labels shared by all its checks so routes and silences can
+// match them.
+func aggregateAndDispatch(ctx context.Context, d *dispatcher, key string, items []notify.Event) {
 	status := highestStatus(items)
-	summary := fmt.Sprintf("%s 檢查彙總（%d）", typeLabel(key), len(items))
-	details := buildAggregateDetails(items)
+	typ := items[0].Type
+	if typ == "" {
+		typ = "unknown"
+	}
 	agg := notify.Event{
-		Service:    key,
-		Type:       key,
-		Status:     status,
-		Summary:    summary,
-		Details:    details,
-		OccurredAt: time.Now(),
-	}
-	dispatch(ctx, cfg, agg, notifiers, log)
+		Service:        key,
+		Type:           typ,
+		Status:         status,
+		PreviousStatus: aggregate.PreviousStatus(items),
+		Labels:         aggregate.SharedLabels(items),
+		OccurredAt:     time.Now(),
+	}
+	name := typeLabel(typ)
+	if by := d.cfg.Notify.AggregateBy; len(by) > 0 {
+		values := make([]string, 0, len(by))
+		for _, k := range by {
+			values = append(values, items[0].Labels[k])
+		}
+		name += " " + strings.Join(values, "/")
+	}
+	agg.SetSummary(i18n.M("aggregate.summary", name, len(items)))
+	agg.SetDetails(buildAggregateDetails(items))
+	agg.Items = buildAggregateItems(items)
+	d.dispatchTo(ctx, agg, func(name string) bool { return !d.perCheck[name] })
 }
 
 func highestStatus(events []notify.Event) string {
@@ -462,23 +643,48 @@ func highestStatus(events []notify.Event) string {
 	return best
 }
 
-func buildAggregateDetails(events []notify.Event) string {
-	var lines []string
+// buildAggregateDetails is the one-line overview of an aggregate; the
+// per-check lines are in buildAggregateItems.
+func buildAggregateDetails(events []notify.Event) i18n.Message {
+	counts := make(map[string]int)
 	for _, ev := range events {
-		if ev.Status == "OK" {
+		if recovered(ev) {
+			counts["RECOVERED"]++
 			continue
 		}
-		detail := ev.Details
-		if ev.Type != "domain_expiry" {
-			detail = fmt.Sprintf("%s: %s", ev.Service, ev.Details)
-		}
-		line := fmt.Sprintf("[%s] %s", ev.Status, detail)
-		lines = append(lines, line)
+		counts[ev.Status]++
+	}
+	if counts["CRIT"]+counts["WARN"]+counts["UNKNOWN"]+counts["RECOVERED"] == 0 {
+		return i18n.M("aggregate.none")
 	}
-	if len(lines) == 0 {
-		return "無 WARN/CRIT"
+	return i18n.M("aggregate.counts", counts["CRIT"], counts["WARN"], counts["UNKNOWN"], counts["RECOVERED"])
+}
+
+// buildAggregateItems lists every failing or recovered check with its
+// message and structured details underneath.
+func buildAggregateItems(events []notify.Event) []check.Detail {
+	var items []check.Detail
+	for _, ev := range events {
+		rec := recovered(ev)
+		if ev.Status == "OK" && !rec {
+			continue
+		}
+		status := ev.Status
+		if rec {
+			status = ev.PreviousStatus + "→" + ev.Status
+		}
+		item := check.Detail{Label: i18n.Text(ev.Service), Value: status}
+		if strings.TrimSpace(ev.Details) != "" {
+			item.Children = append(item.Children, check.Detail{Label: ev.DetailsMessage()})
+		}
+		item.Children = append(item.Children, ev.Items...)
+		items = append(items, item)
 	}
-	return strings.Join(lines, "; ")
+	return items
+}
+
+func recovered(ev notify.Event) bool {
+	return ev.Status == "OK" && ev.PreviousStatus != "" && ev.PreviousStatus != "OK"
 }
 
 func typeLabel(key string) string {
@@ -498,48 +704,116 @@ func typeLabel(key string) string {
 	}
 }
 
-func countChecksByType(cfg *config.Config) map[string]int {
-	out := make(map[string]int)
+func buildGrouper(cfg *config.Config) *aggregate.Grouper {
+	g := aggregate.NewGrouper(cfg.Notify.AggregateBy)
 	for _, c := range cfg.Checks {
 		if c.Type == "" {
 			continue
 		}
-		out[c.Type]++
+		g.Expect(c.Type, c.Labels)
+	}
+	return g
+}
+
+type dispatcher struct {
+	cfg       *config.Config
+	notifiers map[string]notify.Notifier
+	locales   map[string]string
+	routes    []route.Route
+	silences  *silencer
+	outbox    *outbox.Outbox
+	log       *logger.Logger
+	// perCheck are the channels that keep one incident per check. With
+	// aggregation on they get the event of every check, not the aggregates.
+	perCheck map[string]bool
+}
+
+// channelLocales maps each channel to its locale, defaulting to the global one.
+func channelLocales(cfg *config.Config) map[string]string {
+	out := make(map[string]string, len(cfg.Channels))
+	for _, c := range cfg.Channels {
+		locale := c.Locale
+		if locale == "" {
+			locale = cfg.Locale
+		}
+		out[c.Name] = locale
 	}
 	return out
 }
 
-func dispatch(ctx context.Context, cfg *config.Config, event notify.Event, notifiers map[string]notify.Notifier, log *logger.Logger) {
-	for _, route := range cfg.Routes {
-		if !matchRoute(route.Match, event) {
-			continue
+// perCheckChannels returns the PagerDuty channels, whose incidents are keyed
+// by the dedup key of a single check.
+func perCheckChannels(cfg *config.Config) map[string]bool {
+	out := make(map[string]bool)
+	for _, c := range cfg.Channels {
+		if c.Type == "pagerduty" {
+			out[c.Name] = true
 		}
-		for _, name := range route.To {
-			n, ok := notifiers[name]
-			if !ok {
-				continue
-			}
-			if ctx.Err() != nil {
-				return
-			}
-			if err := n.Send(ctx, event); err != nil {
-				if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-					return
+	}
+	return out
+}
+
+func (d *dispatcher) silenced(event notify.Event) bool {
+	if err := d.silences.refresh(); err != nil {
+		d.log.Warnf("reload silence file: %v", err)
+	}
+	name, count, ok := d.silences.match(event, time.Now())
+	if !ok {
+		return false
+	}
+	d.log.Infof("silenced by %q (%d): %s %s", name, count, event.Service, event.Status)
+	return true
+}
+
+func (d *dispatcher) dispatch(ctx context.Context, event notify.Event) {
+	d.dispatchTo(ctx, event, nil)
+}
+
+// dispatchTo sends event to the channels of its routes that keep accepts, or
+// to all of them when keep is nil.
+func (d *dispatcher) dispatchTo(ctx context.Context, event notify.Event, keep func(name string) bool) {
+	if d.silenced(event) {
+		return
+	}
+	for _, i := range route.Select(d.routes, event) {
+		names := d.routes[i].To
+		if keep != nil {
+			names = nil
+			for _, name := range d.routes[i].To {
+				if keep(name) {
+					names = append(names, name)
 				}
-				log.Errorf("notify %s: %v", name, err)
-				continue
 			}
-			log.Infof("notify %s: %s %s", name, event.Service, event.Status)
+		}
+		if !d.sendAll(ctx, names, event) {
+			return
 		}
 	}
 }
 
-func matchRoute(match config.RouteMatch, event notify.Event) bool {
-	if match.Name != "" && match.Name != event.Service {
-		return false
-	}
-	if match.Status != "" && match.Status != event.Status {
-		return false
+// sendAll hands event to the queues of the named channels; delivery results
+// are logged by the queues. It returns false once ctx is done.
+func (d *dispatcher) sendAll(ctx context.Context, names []string, event notify.Event) bool {
+	for _, name := range names {
+		n, ok := d.notifiers[name]
+		if !ok {
+			continue
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		localized := event.Localize(d.locales[name])
+		err := n.Send(ctx, localized)
+		switch {
+		case err == nil:
+		case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+			return false
+		case errors.Is(err, notify.ErrQueueFull):
+			d.log.Warnf("notify %s: queue full, dropped %s %s", name, event.Service, event.Status)
+			keepFailed(d.outbox, name, localized, err, d.log)
+		default:
+			d.log.Errorf("notify %s: %v", name, err)
+		}
 	}
 	return true
 }
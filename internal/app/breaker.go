@@ -0,0 +1,111 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/i18n"
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/core/policy"
+	"services-health-check/internal/core/route"
+)
+
+// metaAlertType is the event type of alerts about healthd's own channels.
+const metaAlertType = "channel"
+
+// withBreakers puts a circuit breaker in front of every notifier. While a
+// circuit is open, events go to the channel's fallback, and a meta-alert
+// reports the broken channel when the circuit opens and again when it closes.
+func (d *dispatcher) withBreakers(ctx context.Context) {
+	for _, c := range d.cfg.Channels {
+		n, ok := d.notifiers[c.Name]
+		if !ok {
+			continue
+		}
+		var fallback notify.Notifier
+		if c.Fallback != "" {
+			fallback = &fallbackNotifier{d: d, from: c.Name, name: c.Fallback}
+		}
+		name, fallbackName := c.Name, c.Fallback
+		b := notify.NewBreaker(n, fallback, d.cfg.Notify.BreakerFailures, d.cfg.Notify.BreakerCooldown)
+		b.OnStateChange = func(state notify.BreakerState, err error) {
+			if state == notify.BreakerOpen {
+				d.log.Errorf("notify %s: circuit open after %d failures: %v", name, b.Threshold, err)
+			} else {
+				d.log.Infof("notify %s: circuit closed", name)
+			}
+			d.channelAlert(ctx, name, fallbackName, state, err)
+		}
+		d.notifiers[name] = b
+	}
+}
+
+// channelAlert sends a meta-alert about channel name to its fallback and to
+// the channels of the matching routes, except name itself.
+func (d *dispatcher) channelAlert(ctx context.Context, name, fallback string, state notify.BreakerState, err error) {
+	event := notify.Event{
+		Service:    name,
+		Type:       metaAlertType,
+		Status:     "CRIT",
+		DedupKey:   policy.DedupKey(metaAlertType + "/" + name),
+		OccurredAt: time.Now(),
+	}
+	items := []check.Detail{{Label: i18n.M("detail.channel"), Value: name}}
+	if state == notify.BreakerOpen {
+		event.PreviousStatus = "OK"
+		event.SetSummary(i18n.M("breaker.open", name))
+		if err != nil {
+			items = append(items, check.Detail{Label: i18n.M("detail.error"), Value: err.Error()})
+		}
+	} else {
+		event.Status, event.PreviousStatus = "OK", "CRIT"
+		event.SetSummary(i18n.M("breaker.closed", name))
+	}
+	if fallback != "" {
+		items = append(items, check.Detail{Label: i18n.M("detail.fallback"), Value: fallback})
+	}
+	event.Items = items
+	if d.silenced(event) {
+		return
+	}
+
+	var targets []string
+	seen := map[string]bool{name: true}
+	add := func(names []string) {
+		for _, n := range names {
+			if !seen[n] {
+				seen[n] = true
+				targets = append(targets, n)
+			}
+		}
+	}
+	if fallback != "" {
+		add([]string{fallback})
+	}
+	for _, i := range route.Select(d.routes, event) {
+		add(d.routes[i].To)
+	}
+	d.sendAll(ctx, targets, event)
+}
+
+// fallbackNotifier hands events of channel from to the notifier of channel
+// name, rendered in the locale of name.
+type fallbackNotifier struct {
+	d    *dispatcher
+	from string
+	name string
+}
+
+func (f *fallbackNotifier) Name() string {
+	return f.name
+}
+
+func (f *fallbackNotifier) Send(ctx context.Context, event notify.Event) error {
+	n, ok := f.d.notifiers[f.name]
+	if !ok {
+		return notify.ErrCircuitOpen
+	}
+	f.d.log.Warnf("notify %s: circuit open, forwarding %s %s to %s", f.from, event.Service, event.Status, f.name)
+	return n.Send(ctx, event.Localize(f.d.locales[f.name]))
+}
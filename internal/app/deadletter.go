@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"services-health-check/internal/config"
+	"services-health-check/internal/core/i18n"
+	"services-health-check/internal/core/outbox"
+)
+
+// DeadLetter runs the "deadletter" subcommand. "list" prints the entries of
+// the dead-letter file; "replay [id...]" re-sends them, all or the given IDs,
+// through the channels of the config and removes those that succeed.
+func DeadLetter(ctx context.Context, configPath string, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: healthd deadletter list|replay [-config path] [id...]")
+	}
+	action := args[0]
+	fs := flag.NewFlagSet("deadletter "+action, flag.ContinueOnError)
+	fs.SetOutput(out)
+	fs.StringVar(&configPath, "config", configPath, "config file path")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	path := deadLetterPath(cfg)
+	if path == "" {
+		return fmt.Errorf("notify.outbox_file or notify.dead_letter_file is not set")
+	}
+	store := outbox.NewStore(path)
+
+	switch action {
+	case "list":
+		if fs.NArg() > 0 {
+			return fmt.Errorf("list takes no arguments")
+		}
+		entries, err := store.Load()
+		if err != nil {
+			return err
+		}
+		return printEntries(out, entries)
+	case "replay":
+		if err := i18n.SetDefault(cfg.Locale); err != nil {
+			return err
+		}
+		log, closeLog, err := buildLogger(cfg.Log)
+		if err != nil {
+			return fmt.Errorf("logger: %w", err)
+		}
+		if closeLog != nil {
+			defer closeLog()
+		}
+		notifiers, err := buildNotifiers(cfg)
+		if err != nil {
+			return fmt.Errorf("build notifiers: %w", err)
+		}
+		withRetry(cfg, notifiers, log)
+		box := outbox.New(outbox.NewStore(cfg.Notify.OutboxFile), store, cfg.Notify.OutboxAttempts, notifiers)
+		res, err := box.Replay(ctx, fs.Args())
+		fmt.Fprintf(out, "replayed: sent=%d failed=%d\n", res.Sent, res.Failed)
+		return err
+	default:
+		return fmt.Errorf("unknown deadletter action %q (want list or replay)", action)
+	}
+}
+
+func printEntries(out io.Writer, entries []outbox.Entry) error {
+	if len(entries) == 0 {
+		_, err := fmt.Fprintln(out, "dead-letter file is empty")
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tCHANNEL\tSERVICE\tSTATUS\tATTEMPTS\tLAST FAILED\tERROR")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			e.ID, e.Channel, e.Event.Service, e.Event.Status, e.Attempts,
+			e.LastFailedAt.Format(time.RFC3339), e.LastError)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"services-health-check/internal/config"
+	"services-health-check/internal/core/escalation"
+	"services-health-check/internal/core/i18n"
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/core/policy"
+	"services-health-check/internal/core/route"
+)
+
+const escalationTick = 10 * time.Second
+
+// escalator re-sends CRIT events to the escalation channels of their routes
+// while the policy still reports the check as CRIT.
+type escalator struct {
+	routes   []config.RouteConfig
+	matchers []route.Route
+	tracker  *escalation.Tracker
+}
+
+func newEscalator(routes []config.RouteConfig, matchers []route.Route, statuses policy.StatusReader) *escalator {
+	return &escalator{
+		routes:   routes,
+		matchers: matchers,
+		tracker:  escalation.NewTracker(statuses),
+	}
+}
+
+// track arms the escalations of the routes event goes to.
+func (e *escalator) track(event notify.Event) {
+	var steps []escalation.Step
+	for _, i := range route.Select(e.matchers, event) {
+		for _, s := range e.routes[i].Escalations {
+			steps = append(steps, escalation.Step{After: s.After, To: s.To})
+		}
+	}
+	e.tracker.Track(event, steps)
+}
+
+func (e *escalator) run(ctx context.Context, d *dispatcher) {
+	ticker := time.NewTicker(escalationTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, p := range e.tracker.Due(now) {
+				ev := p.Event
+				ev.SetSummary(i18n.M("escalation.summary", ev.SummaryMessage(), p.Step.After))
+				ev.Labels = copyLabels(ev.Labels)
+				ev.Labels["escalation"] = p.Step.After.String()
+				ev.OccurredAt = now
+				if d.silenced(ev) {
+					continue
+				}
+				if !d.sendAll(ctx, p.Step.To, ev) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	out := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		out[k] = v
+	}
+	return out
+}
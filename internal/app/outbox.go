@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"services-health-check/internal/config"
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/core/outbox"
+	"services-health-check/internal/utils/logger"
+)
+
+const defaultOutboxInterval = time.Minute
+
+// buildOutbox returns nil unless notify.outbox_file is set. It re-sends
+// through a copy of notifiers, which must not be queued: a failed re-send is
+// handled by the outbox itself.
+func buildOutbox(cfg *config.Config, notifiers map[string]notify.Notifier, log *logger.Logger) *outbox.Outbox {
+	if cfg.Notify.OutboxFile == "" {
+		return nil
+	}
+	targets := make(map[string]notify.Notifier, len(notifiers))
+	for name, n := range notifiers {
+		targets[name] = n
+	}
+	box := outbox.New(outbox.NewStore(cfg.Notify.OutboxFile), outbox.NewStore(deadLetterPath(cfg)), cfg.Notify.OutboxAttempts, targets)
+	box.OnResult = func(e outbox.Entry, err error) {
+		if err != nil {
+			log.Warnf("outbox %s: %s %s (%s): %v", e.Channel, e.Event.Service, e.Event.Status, e.ID, err)
+			return
+		}
+		log.Infof("outbox %s: %s %s (%s) delivered", e.Channel, e.Event.Service, e.Event.Status, e.ID)
+	}
+	return box
+}
+
+// deadLetterPath defaults to the outbox file with a ".dead" suffix before the
+// extension, e.g. outbox.jsonl -> outbox.dead.jsonl.
+func deadLetterPath(cfg *config.Config) string {
+	if cfg.Notify.DeadLetterFile != "" {
+		return cfg.Notify.DeadLetterFile
+	}
+	path := cfg.Notify.OutboxFile
+	if path == "" {
+		return ""
+	}
+	if strings.HasSuffix(path, ".jsonl") {
+		return strings.TrimSuffix(path, ".jsonl") + ".dead.jsonl"
+	}
+	return path + ".dead"
+}
+
+// runOutbox flushes the outbox at startup and then every interval until ctx ends.
+func runOutbox(ctx context.Context, box *outbox.Outbox, interval time.Duration, log *logger.Logger) {
+	if interval <= 0 {
+		interval = defaultOutboxInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		res, err := box.Flush(ctx)
+		if err != nil {
+			log.Errorf("outbox flush: %v", err)
+		}
+		if res.Sent+res.Failed+res.Dead+res.Superseded > 0 {
+			log.Infof("outbox flush: sent=%d failed=%d dead=%d superseded=%d", res.Sent, res.Failed, res.Dead, res.Superseded)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// withDeliveryMarks wraps every notifier so that a successful send drops the
+// outbox entries it makes stale. It must sit under the breakers: an event a
+// breaker forwards to its fallback then only marks the fallback channel, and
+// the entries of the failing channel stay pending.
+func withDeliveryMarks(box *outbox.Outbox, notifiers map[string]notify.Notifier, log *logger.Logger) {
+	if box == nil {
+		return
+	}
+	for name, n := range notifiers {
+		notifiers[name] = &deliveryMark{Notifier: n, box: box, log: log}
+	}
+}
+
+type deliveryMark struct {
+	notify.Notifier
+	box *outbox.Outbox
+	log *logger.Logger
+}
+
+func (m *deliveryMark) Send(ctx context.Context, event notify.Event) error {
+	if err := m.Notifier.Send(ctx, event); err != nil {
+		return err
+	}
+	if err := m.box.Delivered(m.Name(), event); err != nil {
+		m.log.Errorf("outbox %s: drop superseded notifications: %v", m.Name(), err)
+	}
+	return nil
+}
+
+// keepFailed stores a notification that failed on channel name, when an
+// outbox is configured.
+func keepFailed(box *outbox.Outbox, name string, event notify.Event, err error, log *logger.Logger) {
+	if box == nil {
+		return
+	}
+	if err := box.Add(name, event, err); err != nil {
+		log.Errorf("outbox %s: store failed notification: %v", name, err)
+	}
+}
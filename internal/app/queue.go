@@ -0,0 +1,134 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"services-health-check/internal/config"
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/core/outbox"
+	"services-health-check/internal/utils/logger"
+)
+
+const (
+	defaultDrainTimeout = 10 * time.Second
+	queueStatsTick      = time.Minute
+)
+
+// withRetry wraps every notifier in a retry layer for transient errors.
+func withRetry(cfg *config.Config, notifiers map[string]notify.Notifier, log *logger.Logger) {
+	for _, c := range cfg.Channels {
+		n, ok := notifiers[c.Name]
+		if !ok {
+			continue
+		}
+		attempts := c.RetryAttempts
+		if attempts == 0 {
+			attempts = cfg.Notify.RetryAttempts
+		}
+		name := c.Name
+		retry := notify.NewRetry(n, attempts, cfg.Notify.RetryBaseDelay, cfg.Notify.RetryMaxDelay)
+		retry.OnRetry = func(attempt int, wait time.Duration, err error) {
+			log.Warnf("notify %s: attempt %d failed, retrying in %s: %v", name, attempt, wait.Round(time.Millisecond), err)
+		}
+		notifiers[name] = retry
+	}
+}
+
+// startQueues puts every notifier behind its own delivery queue, so a channel
+// that hangs only delays itself. The returned queues replace the entries of
+// notifiers. Events that still fail go to box, if set.
+func startQueues(cfg *config.Config, notifiers map[string]notify.Notifier, box *outbox.Outbox, log *logger.Logger) map[string]*notify.Queue {
+	queues := make(map[string]*notify.Queue, len(notifiers))
+	for _, c := range cfg.Channels {
+		n, ok := notifiers[c.Name]
+		if !ok {
+			continue
+		}
+		size := c.QueueSize
+		if size == 0 {
+			size = cfg.Notify.QueueSize
+		}
+		workers := c.QueueWorkers
+		if workers == 0 {
+			workers = cfg.Notify.QueueWorkers
+		}
+		overflow := c.QueueOverflow
+		if overflow == "" {
+			overflow = cfg.Notify.QueueOverflow
+		}
+		name := c.Name
+		q := notify.NewQueue(n, size, workers, strings.ToLower(overflow))
+		q.OnResult = func(event notify.Event, err error) {
+			if err != nil {
+				log.Errorf("notify %s: %v", name, err)
+				keepFailed(box, name, event, err, log)
+				return
+			}
+			log.Infof("notify %s: %s %s", name, event.Service, event.Status)
+		}
+		queues[name] = q
+		notifiers[name] = q
+	}
+	return queues
+}
+
+// drainQueues closes every queue and waits up to timeout for pending events,
+// then logs the delivery counters of each channel.
+func drainQueues(timeout time.Duration, queues map[string]*notify.Queue, log *logger.Logger) {
+	if timeout <= 0 {
+		timeout = defaultDrainTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	done := make(chan string, len(queues))
+	for name, q := range queues {
+		go func() {
+			if err := q.Close(ctx); err != nil {
+				log.Warnf("queue %s: drain: %v", name, err)
+			}
+			done <- name
+		}()
+	}
+	for range queues {
+		name := <-done
+		logQueueStats(log, name, queues[name].Stats(), notify.QueueStats{})
+	}
+}
+
+// runQueueStats logs the counters of every queue whose traffic changed since
+// the last tick, so drops and back-pressure show up while the service runs.
+func runQueueStats(ctx context.Context, queues map[string]*notify.Queue, log *logger.Logger) {
+	ticker := time.NewTicker(queueStatsTick)
+	defer ticker.Stop()
+
+	last := make(map[string]notify.QueueStats, len(queues))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for name, q := range queues {
+				s := q.Stats()
+				prev := last[name]
+				if s.Enqueued == prev.Enqueued && s.Depth == prev.Depth {
+					continue
+				}
+				logQueueStats(log, name, s, prev)
+				last[name] = s
+			}
+		}
+	}
+}
+
+// logQueueStats warns when events were dropped or senders blocked since prev.
+func logQueueStats(log *logger.Logger, name string, s, prev notify.QueueStats) {
+	logf := log.Infof
+	if s.Dropped > prev.Dropped || s.Blocked > prev.Blocked {
+		logf = log.Warnf
+	}
+	logf("queue %s: delivered=%d failed=%d dropped=%d blocked=%d pending=%d",
+		name, s.Delivered, s.Failed, s.Dropped, s.Blocked, s.Depth)
+}
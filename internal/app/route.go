@@ -0,0 +1,26 @@
+package app
+
+import (
+	"fmt"
+
+	"services-health-check/internal/config"
+	"services-health-check/internal/core/route"
+)
+
+func buildRoutes(items []config.RouteConfig) ([]route.Route, error) {
+	out := make([]route.Route, 0, len(items))
+	for i, c := range items {
+		m := c.Match
+		match, err := route.NewMatcher(m.Name, m.NameRegex, m.Type, m.Status, m.MinStatus, m.Labels)
+		if err != nil {
+			return nil, fmt.Errorf("route index %d: %w", i, err)
+		}
+		out = append(out, route.Route{
+			Match:    match,
+			To:       c.To,
+			Continue: c.Continue == nil || *c.Continue,
+			Default:  c.Default,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"services-health-check/internal/config"
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/core/silence"
+)
+
+const heldTick = 10 * time.Second
+
+// silencer holds the silences from the main config plus those from
+// notify.silence_file, which is re-read whenever it changes on disk.
+type silencer struct {
+	static []silence.Silence
+	file   string
+
+	mu      sync.Mutex
+	modTime time.Time
+	dynamic []silence.Silence
+	fileErr string
+	counts  map[string]int
+}
+
+func buildSilencer(cfg *config.Config) (*silencer, error) {
+	static, err := buildSilences(cfg.Silences)
+	if err != nil {
+		return nil, err
+	}
+	s := &silencer{static: static, file: cfg.Notify.SilenceFile, counts: make(map[string]int)}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func buildSilences(items []config.SilenceConfig) ([]silence.Silence, error) {
+	out := make([]silence.Silence, 0, len(items))
+	for _, c := range items {
+		match := silence.Matcher{
+			Name:   c.Match.Name,
+			Type:   c.Match.Type,
+			Status: c.Match.Status,
+			Labels: c.Match.Labels,
+		}
+		s, err := silence.New(c.Name, match, c.Start, c.End, c.Schedule, c.Duration)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// match returns the name of the first active silence covering event and how
+// many events it has suppressed so far.
+func (s *silencer) match(event notify.Event, now time.Time) (string, int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, list := range [][]silence.Silence{s.static, s.dynamic} {
+		for _, sil := range list {
+			if !sil.Silences(event, now) {
+				continue
+			}
+			s.counts[sil.Name]++
+			return sil.Name, s.counts[sil.Name], true
+		}
+	}
+	return "", 0, false
+}
+
+// covers reports whether an active silence covers event, without counting
+// it as suppressed.
+func (s *silencer) covers(event notify.Event, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, list := range [][]silence.Silence{s.static, s.dynamic} {
+		for _, sil := range list {
+			if sil.Silences(event, now) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// runHeld announces, once their silence is over, the state changes of checks
+// that happened while they were silenced and are still current.
+func runHeld(ctx context.Context, d *dispatcher, held *silence.Held, deliver func(notify.Event)) {
+	ticker := time.NewTicker(heldTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := d.silences.refresh(); err != nil {
+				d.log.Warnf("reload silence file: %v", err)
+			}
+			expired := held.Expired(func(event notify.Event) bool {
+				return d.silences.covers(event, now)
+			})
+			for _, event := range expired {
+				d.log.Infof("silence over: announcing %s %s", event.Service, event.Status)
+				event.OccurredAt = now
+				deliver(event)
+			}
+		}
+	}
+}
+
+// refresh re-reads the silence file if it changed. A broken file keeps the
+// previous silences and its error is reported only once.
+func (s *silencer) refresh() error {
+	err := s.reload()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.fileErr = ""
+		return nil
+	}
+	if err.Error() == s.fileErr {
+		return nil
+	}
+	s.fileErr = err.Error()
+	return err
+}
+
+func (s *silencer) reload() error {
+	if s.file == "" {
+		return nil
+	}
+	info, err := os.Stat(s.file)
+	if err != nil {
+		s.mu.Lock()
+		s.dynamic = nil
+		s.modTime = time.Time{}
+		s.mu.Unlock()
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	s.mu.Lock()
+	unchanged := info.ModTime().Equal(s.modTime)
+	s.mu.Unlock()
+	if unchanged {
+		return nil
+	}
+
+	items, err := config.LoadSilences(s.file)
+	if err != nil {
+		return err
+	}
+	dynamic, err := buildSilences(items)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.dynamic = dynamic
+	s.modTime = info.ModTime()
+	s.mu.Unlock()
+	return nil
+}
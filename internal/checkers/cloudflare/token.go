@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/i18n"
 )
 
 type TokenChecker struct {
@@ -37,7 +38,7 @@ func (c *TokenChecker) Name() string {
 
 func (c *TokenChecker) Check(ctx context.Context) (check.Result, error) {
 	if strings.TrimSpace(c.Token) == "" {
-		return check.Result{Name: c.NameValue, Status: check.StatusUnknown, Message: "缺少 Cloudflare token", CheckedAt: time.Now()}, fmt.Errorf("token required")
+		return check.Result{Name: c.NameValue, Status: check.StatusUnknown, Message: i18n.M("cloudflare.missing_token"), CheckedAt: time.Now()}, fmt.Errorf("token required")
 	}
 
 	timeout := c.Timeout
@@ -52,40 +53,52 @@ func (c *TokenChecker) Check(ctx context.Context) (check.Result, error) {
 	}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return check.Result{Name: c.NameValue, Status: check.StatusCrit, Message: "建立請求失敗: " + err.Error(), CheckedAt: time.Now()}, err
+		return check.Result{Name: c.NameValue, Status: check.StatusCrit, Message: i18n.M("cloudflare.request_failed", err.Error()), CheckedAt: time.Now()}, err
 	}
 	req.Header.Set("Authorization", "Bearer "+c.Token)
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return check.Result{Name: c.NameValue, Status: check.StatusCrit, Message: "連線失敗: " + err.Error(), CheckedAt: time.Now()}, err
+		return check.Result{Name: c.NameValue, Status: check.StatusCrit, Message: i18n.M("check.connect_failed", err.Error()), CheckedAt: time.Now()}, err
 	}
 	defer resp.Body.Close()
 
 	var payload tokenVerifyResponse
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return check.Result{Name: c.NameValue, Status: check.StatusCrit, Message: "解析回應失敗: " + err.Error(), CheckedAt: time.Now()}, err
+		return check.Result{Name: c.NameValue, Status: check.StatusCrit, Message: i18n.M("check.decode_failed", err.Error()), CheckedAt: time.Now()}, err
 	}
 
 	status := check.StatusOK
-	message := "token 正常"
+	message := i18n.M("cloudflare.ok")
 	if !payload.Success {
 		status = check.StatusCrit
-		message = "token 驗證失敗: " + joinErrors(payload.Errors)
+		message = i18n.M("cloudflare.verify_failed", joinErrors(payload.Errors))
 	} else if strings.ToLower(payload.Result.Status) != "active" && payload.Result.Status != "" {
 		status = check.StatusWarn
-		message = fmt.Sprintf("token 狀態: %s", payload.Result.Status)
+		message = i18n.M("cloudflare.status", payload.Result.Status)
 	}
 
 	return check.Result{
 		Name:      c.NameValue,
 		Status:    status,
 		Message:   message,
+		Details:   tokenDetails(payload),
 		Metrics:   map[string]any{"status": payload.Result.Status, "id": payload.Result.ID},
 		CheckedAt: time.Now(),
 	}, nil
 }
 
+func tokenDetails(payload tokenVerifyResponse) []check.Detail {
+	var out []check.Detail
+	if payload.Result.Status != "" {
+		out = append(out, check.Detail{Label: i18n.M("detail.token_status"), Value: payload.Result.Status})
+	}
+	if payload.Result.ID != "" {
+		out = append(out, check.Detail{Label: i18n.M("detail.token_id"), Value: payload.Result.ID})
+	}
+	return out
+}
+
 func joinErrors(errs []errorMessage) string {
 	if len(errs) == 0 {
 		return "token verify failed"
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/i18n"
 
 	"github.com/likexian/whois"
 	"github.com/likexian/whois-parser"
@@ -31,15 +32,16 @@ func (c *ExpiryChecker) Name() string {
 
 func (c *ExpiryChecker) Check(ctx context.Context) (check.Result, error) {
 	if strings.TrimSpace(c.Domain) == "" {
-		return check.Result{Name: c.NameValue, Status: check.StatusUnknown, Message: "缺少 domain", CheckedAt: time.Now()}, fmt.Errorf("domain required")
+		return check.Result{Name: c.NameValue, Status: check.StatusUnknown, Message: i18n.M("domain.missing"), CheckedAt: time.Now()}, fmt.Errorf("domain required")
 	}
 
 	cctx, cancel := c.withJitter(ctx)
 	defer cancel()
 
+	target := check.Detail{Label: i18n.M("detail.domain"), Value: c.Domain, Kind: check.KindDomain}
 	exp, err := c.lookupExpiration(cctx)
 	if err != nil {
-		return check.Result{Name: c.NameValue, Status: check.StatusCrit, Message: fmt.Sprintf("查詢失敗（%s）: %s", c.Domain, err.Error()), CheckedAt: time.Now()}, err
+		return check.Result{Name: c.NameValue, Status: check.StatusCrit, Message: i18n.M("domain.lookup_failed", c.Domain, err.Error()), Details: []check.Detail{target}, CheckedAt: time.Now()}, err
 	}
 	until := time.Until(exp)
 	remaining := formatDurationDHMS(until)
@@ -54,22 +56,27 @@ func (c *ExpiryChecker) Check(ctx context.Context) (check.Result, error) {
 	}
 
 	status := check.StatusOK
-	message := fmt.Sprintf("網域尚有 %s（%s）", remaining, c.Domain)
+	message := i18n.M("domain.remaining", remaining, c.Domain)
 	if until <= 0 {
 		status = check.StatusCrit
-		message = fmt.Sprintf("網域已過期（%s）", c.Domain)
+		message = i18n.M("domain.expired", c.Domain)
 	} else if until <= crit {
 		status = check.StatusCrit
-		message = fmt.Sprintf("網域即將過期（%s）：%s", c.Domain, remaining)
+		message = i18n.M("domain.expiring", c.Domain, remaining)
 	} else if until <= warn {
 		status = check.StatusWarn
-		message = fmt.Sprintf("網域即將過期（%s）：%s", c.Domain, remaining)
+		message = i18n.M("domain.expiring", c.Domain, remaining)
 	}
 
 	return check.Result{
-		Name:      c.NameValue,
-		Status:    status,
-		Message:   message,
+		Name:    c.NameValue,
+		Status:  status,
+		Message: message,
+		Details: []check.Detail{
+			target,
+			{Label: i18n.M("detail.expires_at"), Value: exp.Format(time.RFC3339)},
+			{Label: i18n.M("detail.remaining"), Value: remaining},
+		},
 		Metrics:   map[string]any{"expiration": exp.Format(time.RFC3339)},
 		CheckedAt: time.Now(),
 	}, nil
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/i18n"
 )
 
 type Checker struct {
@@ -22,12 +23,13 @@ func (c *Checker) Check(ctx context.Context) (check.Result, error) {
 	client := &http.Client{Timeout: c.Timeout}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL, nil)
 	if err != nil {
-		return check.Result{Name: c.NameValue, Status: check.StatusUnknown, Message: "請求建立失敗: " + err.Error(), CheckedAt: time.Now()}, err
+		return check.Result{Name: c.NameValue, Status: check.StatusUnknown, Message: i18n.M("check.request_failed", err.Error()), CheckedAt: time.Now()}, err
 	}
 
+	target := check.Detail{Label: i18n.M("detail.url"), Value: c.URL, Kind: check.KindURL}
 	resp, err := client.Do(req)
 	if err != nil {
-		return check.Result{Name: c.NameValue, Status: check.StatusCrit, Message: "連線失敗: " + err.Error(), CheckedAt: time.Now()}, err
+		return check.Result{Name: c.NameValue, Status: check.StatusCrit, Message: i18n.M("check.connect_failed", err.Error()), Details: []check.Detail{target}, CheckedAt: time.Now()}, err
 	}
 	defer resp.Body.Close()
 
@@ -37,9 +39,13 @@ func (c *Checker) Check(ctx context.Context) (check.Result, error) {
 	}
 
 	return check.Result{
-		Name:      c.NameValue,
-		Status:    status,
-		Message:   "HTTP 狀態: " + resp.Status,
+		Name:    c.NameValue,
+		Status:  status,
+		Message: i18n.M("http.status", resp.Status),
+		Details: []check.Detail{
+			target,
+			{Label: i18n.M("detail.http_status"), Value: resp.Status},
+		},
 		Metrics:   map[string]any{"status_code": resp.StatusCode},
 		CheckedAt: time.Now(),
 	}, nil
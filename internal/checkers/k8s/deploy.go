@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/i18n"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -33,7 +34,7 @@ func (c *PodChecker) Name() string {
 func (c *PodChecker) Check(ctx context.Context) (check.Result, error) {
 	clientset, err := c.buildClient()
 	if err != nil {
-		return check.Result{Name: c.NameValue, Status: check.StatusCrit, Message: err.Error(), CheckedAt: time.Now()}, err
+		return check.Result{Name: c.NameValue, Status: check.StatusCrit, Message: i18n.Text(err.Error()), CheckedAt: time.Now()}, err
 	}
 
 	ns := c.Namespace
@@ -46,7 +47,7 @@ func (c *PodChecker) Check(ctx context.Context) (check.Result, error) {
 
 	deploys, err := clientset.AppsV1().Deployments(ns).List(ctx, metav1.ListOptions{LabelSelector: c.LabelSelector})
 	if err != nil {
-		return check.Result{Name: c.NameValue, Status: check.StatusCrit, Message: err.Error(), CheckedAt: time.Now()}, err
+		return check.Result{Name: c.NameValue, Status: check.StatusCrit, Message: i18n.Text(err.Error()), CheckedAt: time.Now()}, err
 	}
 
 	total := len(deploys.Items)
@@ -69,19 +70,22 @@ func (c *PodChecker) Check(ctx context.Context) (check.Result, error) {
 	}
 
 	status := check.StatusOK
-	message := "deployments healthy"
+	message := i18n.M("k8s.healthy")
 
 	if total == 0 {
 		status = check.StatusWarn
-		message = "找不到任何 Deployment"
+		message = i18n.M("k8s.none")
 	} else if c.MinReady > 0 && ready < c.MinReady {
 		status = check.StatusCrit
-		message = fmt.Sprintf("就緒數不足：%d（最低 %d）", ready, c.MinReady)
+		message = i18n.M("k8s.min_ready", ready, c.MinReady)
 	} else if unready > 0 || ready < total {
 		status = check.StatusWarn
-		message = fmt.Sprintf("就緒 %d/%d", ready, total)
+		message = i18n.M("k8s.ready", ready, total)
 	}
 
+	details := []check.Detail{
+		{Label: i18n.M("detail.ready"), Value: fmt.Sprintf("%d/%d", ready, total)},
+	}
 	if len(problems) > 0 {
 		limit := c.ProblemLimit
 		if limit <= 0 {
@@ -90,13 +94,18 @@ func (c *PodChecker) Check(ctx context.Context) (check.Result, error) {
 		if len(problems) < limit {
 			limit = len(problems)
 		}
-		message = fmt.Sprintf("%s；例: %s", message, strings.Join(problems[:limit], "；"))
+		unreadyItem := check.Detail{Label: i18n.M("detail.unready"), Value: fmt.Sprintf("%d", unready)}
+		for _, p := range problems[:limit] {
+			unreadyItem.Children = append(unreadyItem.Children, check.Detail{Value: p, Kind: check.KindPod})
+		}
+		details = append(details, unreadyItem)
 	}
 
 	return check.Result{
 		Name:      c.NameValue,
 		Status:    status,
 		Message:   message,
+		Details:   details,
 		Metrics:   map[string]any{"total": total, "ready": ready, "unready": unready, "problems": problems},
 		CheckedAt: time.Now(),
 	}, nil
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/i18n"
 )
 
 type Checker struct {
@@ -27,7 +28,7 @@ func (c *Checker) Name() string {
 func (c *Checker) Check(ctx context.Context) (check.Result, error) {
 	addr := c.Address
 	if addr == "" {
-		return check.Result{Name: c.NameValue, Status: check.StatusUnknown, Message: "缺少 address", CheckedAt: time.Now()}, fmt.Errorf("address required")
+		return check.Result{Name: c.NameValue, Status: check.StatusUnknown, Message: i18n.M("ssl.missing_address"), CheckedAt: time.Now()}, fmt.Errorf("address required")
 	}
 
 	serverName := c.ServerName
@@ -45,14 +46,18 @@ func (c *Checker) Check(ctx context.Context) (check.Result, error) {
 		ServerName:         serverName,
 		InsecureSkipVerify: c.SkipVerify,
 	})
+	target := []check.Detail{
+		{Label: i18n.M("detail.host"), Value: serverName, Kind: check.KindDomain},
+		{Label: i18n.M("detail.address"), Value: addr},
+	}
 	if err != nil {
-		return check.Result{Name: c.NameValue, Status: check.StatusCrit, Message: fmt.Sprintf("TLS 連線失敗 %s (SNI %s): %v", addr, serverName, err), CheckedAt: time.Now()}, err
+		return check.Result{Name: c.NameValue, Status: check.StatusCrit, Message: i18n.M("ssl.dial_failed", addr, serverName, err.Error()), Details: target, CheckedAt: time.Now()}, err
 	}
 	defer conn.Close()
 
 	state := conn.ConnectionState()
 	if len(state.PeerCertificates) == 0 {
-		return check.Result{Name: c.NameValue, Status: check.StatusUnknown, Message: "未取得憑證", CheckedAt: time.Now()}, fmt.Errorf("no peer certificates")
+		return check.Result{Name: c.NameValue, Status: check.StatusUnknown, Message: i18n.M("ssl.no_certificate"), CheckedAt: time.Now()}, fmt.Errorf("no peer certificates")
 	}
 
 	cert := state.PeerCertificates[0]
@@ -68,22 +73,26 @@ func (c *Checker) Check(ctx context.Context) (check.Result, error) {
 	}
 
 	status := check.StatusOK
-	message := fmt.Sprintf("憑證尚有 %s", formatDurationDHMS(until))
+	message := i18n.M("ssl.remaining", formatDurationDHMS(until))
 	if until <= 0 {
 		status = check.StatusCrit
-		message = "憑證已過期"
+		message = i18n.M("ssl.expired")
 	} else if until <= crit {
 		status = check.StatusCrit
-		message = fmt.Sprintf("憑證即將過期：%s", formatDurationDHMS(until))
+		message = i18n.M("ssl.expiring", formatDurationDHMS(until))
 	} else if until <= warn {
 		status = check.StatusWarn
-		message = fmt.Sprintf("憑證即將過期：%s", formatDurationDHMS(until))
+		message = i18n.M("ssl.expiring", formatDurationDHMS(until))
 	}
 
 	return check.Result{
-		Name:      c.NameValue,
-		Status:    status,
-		Message:   message,
+		Name:    c.NameValue,
+		Status:  status,
+		Message: message,
+		Details: append(target,
+			check.Detail{Label: i18n.M("detail.expires_at"), Value: cert.NotAfter.Format(time.RFC3339)},
+			check.Detail{Label: i18n.M("detail.remaining"), Value: formatDurationDHMS(until)},
+		),
 		Metrics:   map[string]any{"not_after": cert.NotAfter.Format(time.RFC3339), "days_left": int(until.Hours() / 24)},
 		CheckedAt: time.Now(),
 	}, nil
@@ -3,12 +3,16 @@ package config
 import "time"
 
 type Config struct {
-	Checks   []CheckConfig   `yaml:"checks" mapstructure:"checks"`
-	Policies []PolicyConfig  `yaml:"policies" mapstructure:"policies"`
-	Channels []ChannelConfig `yaml:"channels" mapstructure:"channels"`
-	Routes   []RouteConfig   `yaml:"routes" mapstructure:"routes"`
-	Log      LogConfig       `yaml:"log" mapstructure:"log"`
-	Notify   NotifyConfig    `yaml:"notify" mapstructure:"notify"`
+	Checks    []CheckConfig   `yaml:"checks" mapstructure:"checks"`
+	Policies  []PolicyConfig  `yaml:"policies" mapstructure:"policies"`
+	Channels  []ChannelConfig `yaml:"channels" mapstructure:"channels"`
+	Routes    []RouteConfig   `yaml:"routes" mapstructure:"routes"`
+	Log       LogConfig       `yaml:"log" mapstructure:"log"`
+	Notify    NotifyConfig    `yaml:"notify" mapstructure:"notify"`
+	Scheduler SchedulerConfig `yaml:"scheduler" mapstructure:"scheduler"`
+	State     StateConfig     `yaml:"state" mapstructure:"state"`
+	Silences  []SilenceConfig `yaml:"silences" mapstructure:"silences"`
+	Locale    string          `yaml:"locale" mapstructure:"locale" env:"LOCALE"`
 }
 
 func DefaultConfig() Config {
@@ -16,67 +20,171 @@ func DefaultConfig() Config {
 }
 
 type CheckConfig struct {
-	Type          string        `yaml:"type" mapstructure:"type" env:"CHECK_TYPE"`
-	Name          string        `yaml:"name" mapstructure:"name" env:"CHECK_NAME"`
-	URL           string        `yaml:"url" mapstructure:"url" env:"CHECK_URL"`
-	Interval      time.Duration `yaml:"interval" mapstructure:"interval" env:"CHECK_INTERVAL"`
-	Schedule      string        `yaml:"schedule" mapstructure:"schedule" env:"CHECK_SCHEDULE"`
-	Timeout       time.Duration `yaml:"timeout" mapstructure:"timeout" env:"CHECK_TIMEOUT"`
-	Address       string        `yaml:"address" mapstructure:"address" env:"CHECK_ADDRESS"`
-	ServerName    string        `yaml:"server_name" mapstructure:"server_name" env:"CHECK_SERVER_NAME"`
-	Domain        string        `yaml:"domain" mapstructure:"domain" env:"CHECK_DOMAIN"`
-	Token         string        `yaml:"token" mapstructure:"token" env:"CHECK_TOKEN"`
-	WarnBefore    time.Duration `yaml:"warn_before" mapstructure:"warn_before" env:"CHECK_WARN_BEFORE"`
-	CritBefore    time.Duration `yaml:"crit_before" mapstructure:"crit_before" env:"CHECK_CRIT_BEFORE"`
-	RDAPBaseURL   string        `yaml:"rdap_base_url" mapstructure:"rdap_base_url" env:"CHECK_RDAP_BASE_URL"`
-	RDAPBaseURLs  []string      `yaml:"rdap_base_urls" mapstructure:"rdap_base_urls"`
-	SkipVerify    bool          `yaml:"skip_verify" mapstructure:"skip_verify" env:"CHECK_SKIP_VERIFY"`
-	Namespace     string        `yaml:"namespace" mapstructure:"namespace" env:"CHECK_NAMESPACE"`
-	LabelSelector string        `yaml:"label_selector" mapstructure:"label_selector" env:"CHECK_LABEL_SELECTOR"`
-	Kubeconfig    string        `yaml:"kubeconfig" mapstructure:"kubeconfig" env:"CHECK_KUBECONFIG"`
-	Context       string        `yaml:"context" mapstructure:"context" env:"CHECK_CONTEXT"`
-	MinReady      int           `yaml:"min_ready" mapstructure:"min_ready" env:"CHECK_MIN_READY"`
+	Type          string            `yaml:"type" mapstructure:"type" env:"CHECK_TYPE"`
+	Name          string            `yaml:"name" mapstructure:"name" env:"CHECK_NAME"`
+	URL           string            `yaml:"url" mapstructure:"url" env:"CHECK_URL"`
+	Interval      time.Duration     `yaml:"interval" mapstructure:"interval" env:"CHECK_INTERVAL"`
+	Schedule      string            `yaml:"schedule" mapstructure:"schedule" env:"CHECK_SCHEDULE"`
+	Timeout       time.Duration     `yaml:"timeout" mapstructure:"timeout" env:"CHECK_TIMEOUT"`
+	Address       string            `yaml:"address" mapstructure:"address" env:"CHECK_ADDRESS"`
+	ServerName    string            `yaml:"server_name" mapstructure:"server_name" env:"CHECK_SERVER_NAME"`
+	Domain        string            `yaml:"domain" mapstructure:"domain" env:"CHECK_DOMAIN"`
+	Token         string            `yaml:"token" mapstructure:"token" env:"CHECK_TOKEN"`
+	WarnBefore    time.Duration     `yaml:"warn_before" mapstructure:"warn_before" env:"CHECK_WARN_BEFORE"`
+	CritBefore    time.Duration     `yaml:"crit_before" mapstructure:"crit_before" env:"CHECK_CRIT_BEFORE"`
+	RDAPBaseURL   string            `yaml:"rdap_base_url" mapstructure:"rdap_base_url" env:"CHECK_RDAP_BASE_URL"`
+	RDAPBaseURLs  []string          `yaml:"rdap_base_urls" mapstructure:"rdap_base_urls"`
+	SkipVerify    bool              `yaml:"skip_verify" mapstructure:"skip_verify" env:"CHECK_SKIP_VERIFY"`
+	Namespace     string            `yaml:"namespace" mapstructure:"namespace" env:"CHECK_NAMESPACE"`
+	LabelSelector string            `yaml:"label_selector" mapstructure:"label_selector" env:"CHECK_LABEL_SELECTOR"`
+	Kubeconfig    string            `yaml:"kubeconfig" mapstructure:"kubeconfig" env:"CHECK_KUBECONFIG"`
+	Context       string            `yaml:"context" mapstructure:"context" env:"CHECK_CONTEXT"`
+	MinReady      int               `yaml:"min_ready" mapstructure:"min_ready" env:"CHECK_MIN_READY"`
+	Policy        string            `yaml:"policy" mapstructure:"policy" env:"CHECK_POLICY"`
+	DependsOn     []string          `yaml:"depends_on" mapstructure:"depends_on"`
+	Labels        map[string]string `yaml:"labels" mapstructure:"labels"`
 }
 
 type PolicyConfig struct {
 	Name             string        `yaml:"name" mapstructure:"name" env:"POLICY_NAME"`
 	Cooldown         time.Duration `yaml:"cooldown" mapstructure:"cooldown" env:"POLICY_COOLDOWN"`
 	NotifyOnRecovery bool          `yaml:"notify_on_recovery" mapstructure:"notify_on_recovery" env:"POLICY_NOTIFY_ON_RECOVERY"`
+	FailureThreshold int           `yaml:"failure_threshold" mapstructure:"failure_threshold" env:"POLICY_FAILURE_THRESHOLD"`
+	SuccessThreshold int           `yaml:"success_threshold" mapstructure:"success_threshold" env:"POLICY_SUCCESS_THRESHOLD"`
+	RepeatInterval   time.Duration `yaml:"repeat_interval" mapstructure:"repeat_interval" env:"POLICY_REPEAT_INTERVAL"`
+	MaxRepeats       int           `yaml:"max_repeats" mapstructure:"max_repeats" env:"POLICY_MAX_REPEATS"`
+	FlapWindow       int           `yaml:"flap_window" mapstructure:"flap_window" env:"POLICY_FLAP_WINDOW"`
+	FlapHigh         float64       `yaml:"flap_high_threshold" mapstructure:"flap_high_threshold" env:"POLICY_FLAP_HIGH_THRESHOLD"`
+	FlapLow          float64       `yaml:"flap_low_threshold" mapstructure:"flap_low_threshold" env:"POLICY_FLAP_LOW_THRESHOLD"`
+}
+
+// DefaultPolicy returns the policy used by checks without an explicit
+// `policy`: the one named "default", otherwise the first one.
+func (c *Config) DefaultPolicy() (PolicyConfig, bool) {
+	for _, p := range c.Policies {
+		if p.Name == "default" {
+			return p, true
+		}
+	}
+	if len(c.Policies) > 0 {
+		return c.Policies[0], true
+	}
+	return PolicyConfig{}, false
 }
 
 type ChannelConfig struct {
-	Type              string        `yaml:"type" mapstructure:"type" env:"CHANNEL_TYPE"`
-	Name              string        `yaml:"name" mapstructure:"name" env:"CHANNEL_NAME"`
-	URL               string        `yaml:"url" mapstructure:"url" env:"CHANNEL_URL"`
-	Timeout           time.Duration `yaml:"timeout" mapstructure:"timeout" env:"CHANNEL_TIMEOUT"`
-	Username          string        `yaml:"username" mapstructure:"username" env:"CHANNEL_USERNAME"`
-	SMTPHost          string        `yaml:"smtp_host" mapstructure:"smtp_host" env:"CHANNEL_SMTP_HOST"`
-	SMTPPort          int           `yaml:"smtp_port" mapstructure:"smtp_port" env:"CHANNEL_SMTP_PORT"`
-	SMTPUsername      string        `yaml:"smtp_username" mapstructure:"smtp_username" env:"CHANNEL_SMTP_USERNAME"`
-	SMTPPassword      string        `yaml:"smtp_password" mapstructure:"smtp_password" env:"CHANNEL_SMTP_PASSWORD"`
-	SMTPFrom          string        `yaml:"smtp_from" mapstructure:"smtp_from" env:"CHANNEL_SMTP_FROM"`
-	SMTPTo            []string      `yaml:"smtp_to" mapstructure:"smtp_to" env:"CHANNEL_SMTP_TO"`
-	SMTPSubject       string        `yaml:"smtp_subject" mapstructure:"smtp_subject" env:"CHANNEL_SMTP_SUBJECT"`
-	SMTPImplicitTLS   bool          `yaml:"smtp_implicit_tls" mapstructure:"smtp_implicit_tls" env:"CHANNEL_SMTP_IMPLICIT_TLS"`
-	SMTPSkipVerifyTLS bool          `yaml:"smtp_skip_verify" mapstructure:"smtp_skip_verify" env:"CHANNEL_SMTP_SKIP_VERIFY"`
+	Type              string            `yaml:"type" mapstructure:"type" env:"CHANNEL_TYPE"`
+	Name              string            `yaml:"name" mapstructure:"name" env:"CHANNEL_NAME"`
+	URL               string            `yaml:"url" mapstructure:"url" env:"CHANNEL_URL"`
+	Locale            string            `yaml:"locale" mapstructure:"locale" env:"CHANNEL_LOCALE"`
+	Timeout           time.Duration     `yaml:"timeout" mapstructure:"timeout" env:"CHANNEL_TIMEOUT"`
+	Username          string            `yaml:"username" mapstructure:"username" env:"CHANNEL_USERNAME"`
+	Method            string            `yaml:"method" mapstructure:"method" env:"CHANNEL_METHOD"`
+	Headers           map[string]string `yaml:"headers" mapstructure:"headers" env:"CHANNEL_HEADERS"`
+	TitleTemplate     string            `yaml:"title_template" mapstructure:"title_template" env:"CHANNEL_TITLE_TEMPLATE"`
+	BodyTemplate      string            `yaml:"body_template" mapstructure:"body_template" env:"CHANNEL_BODY_TEMPLATE"`
+	HMACSecret        string            `yaml:"hmac_secret" mapstructure:"hmac_secret" env:"CHANNEL_HMAC_SECRET"`
+	HMACHeader        string            `yaml:"hmac_header" mapstructure:"hmac_header" env:"CHANNEL_HMAC_HEADER"`
+	SMTPHost          string            `yaml:"smtp_host" mapstructure:"smtp_host" env:"CHANNEL_SMTP_HOST"`
+	SMTPPort          int               `yaml:"smtp_port" mapstructure:"smtp_port" env:"CHANNEL_SMTP_PORT"`
+	SMTPUsername      string            `yaml:"smtp_username" mapstructure:"smtp_username" env:"CHANNEL_SMTP_USERNAME"`
+	SMTPPassword      string            `yaml:"smtp_password" mapstructure:"smtp_password" env:"CHANNEL_SMTP_PASSWORD"`
+	SMTPFrom          string            `yaml:"smtp_from" mapstructure:"smtp_from" env:"CHANNEL_SMTP_FROM"`
+	SMTPTo            []string          `yaml:"smtp_to" mapstructure:"smtp_to" env:"CHANNEL_SMTP_TO"`
+	SMTPSubject       string            `yaml:"smtp_subject" mapstructure:"smtp_subject" env:"CHANNEL_SMTP_SUBJECT"`
+	SMTPImplicitTLS   bool              `yaml:"smtp_implicit_tls" mapstructure:"smtp_implicit_tls" env:"CHANNEL_SMTP_IMPLICIT_TLS"`
+	SMTPSkipVerifyTLS bool              `yaml:"smtp_skip_verify" mapstructure:"smtp_skip_verify" env:"CHANNEL_SMTP_SKIP_VERIFY"`
+	PagerDutyKey      string            `yaml:"pagerduty_routing_key" mapstructure:"pagerduty_routing_key" env:"CHANNEL_PAGERDUTY_ROUTING_KEY"`
+	TelegramBotToken  string            `yaml:"telegram_bot_token" mapstructure:"telegram_bot_token" env:"CHANNEL_TELEGRAM_BOT_TOKEN"`
+	TelegramChatID    string            `yaml:"telegram_chat_id" mapstructure:"telegram_chat_id" env:"CHANNEL_TELEGRAM_CHAT_ID"`
+	TelegramParseMode string            `yaml:"telegram_parse_mode" mapstructure:"telegram_parse_mode" env:"CHANNEL_TELEGRAM_PARSE_MODE"`
+	LineToken         string            `yaml:"line_token" mapstructure:"line_token" env:"CHANNEL_LINE_TOKEN"`
+	LineTo            []string          `yaml:"line_to" mapstructure:"line_to" env:"CHANNEL_LINE_TO"`
+	LineFlex          bool              `yaml:"line_flex" mapstructure:"line_flex" env:"CHANNEL_LINE_FLEX"`
+	QueueSize         int               `yaml:"queue_size" mapstructure:"queue_size" env:"CHANNEL_QUEUE_SIZE"`
+	QueueWorkers      int               `yaml:"queue_workers" mapstructure:"queue_workers" env:"CHANNEL_QUEUE_WORKERS"`
+	QueueOverflow     string            `yaml:"queue_overflow" mapstructure:"queue_overflow" env:"CHANNEL_QUEUE_OVERFLOW"`
+	RetryAttempts     int               `yaml:"retry_attempts" mapstructure:"retry_attempts" env:"CHANNEL_RETRY_ATTEMPTS"`
+	Fallback          string            `yaml:"fallback" mapstructure:"fallback" env:"CHANNEL_FALLBACK"`
 }
 
+// RouteConfig sends matching events to To. Continue defaults to true; set it
+// to false to stop at this route. A Default route only applies when no other
+// route matched.
 type RouteConfig struct {
-	Match RouteMatch `yaml:"match" mapstructure:"match"`
-	To    []string   `yaml:"to" mapstructure:"to" env:"ROUTE_TO"`
+	Match       RouteMatch         `yaml:"match" mapstructure:"match"`
+	To          []string           `yaml:"to" mapstructure:"to" env:"ROUTE_TO"`
+	Continue    *bool              `yaml:"continue" mapstructure:"continue"`
+	Default     bool               `yaml:"default" mapstructure:"default"`
+	Escalations []EscalationConfig `yaml:"escalations" mapstructure:"escalations"`
+}
+
+// EscalationConfig re-sends a CRIT event to To once it has stayed unresolved for After.
+type EscalationConfig struct {
+	After time.Duration `yaml:"after" mapstructure:"after"`
+	To    []string      `yaml:"to" mapstructure:"to"`
 }
 
+// RouteMatch selects events; empty fields match everything. Name may be a
+// glob, NameRegex must match the whole name and MinStatus matches that
+// status and anything more severe.
 type RouteMatch struct {
-	Name   string `yaml:"name" mapstructure:"name" env:"ROUTE_MATCH_NAME"`
-	Status string `yaml:"status" mapstructure:"status" env:"ROUTE_MATCH_STATUS"`
+	Name      string            `yaml:"name" mapstructure:"name" env:"ROUTE_MATCH_NAME"`
+	NameRegex string            `yaml:"name_regex" mapstructure:"name_regex" env:"ROUTE_MATCH_NAME_REGEX"`
+	Type      string            `yaml:"type" mapstructure:"type" env:"ROUTE_MATCH_TYPE"`
+	Status    string            `yaml:"status" mapstructure:"status" env:"ROUTE_MATCH_STATUS"`
+	MinStatus string            `yaml:"min_status" mapstructure:"min_status" env:"ROUTE_MATCH_MIN_STATUS"`
+	Labels    map[string]string `yaml:"labels" mapstructure:"labels"`
 }
 
 type NotifyConfig struct {
 	ProblemLimit    int           `yaml:"problem_limit" mapstructure:"problem_limit" env:"PROBLEM_LIMIT"`
 	AggregateByType bool          `yaml:"aggregate_by_type" mapstructure:"aggregate_by_type" env:"NOTIFY_AGGREGATE_BY_TYPE"`
 	AggregateWindow time.Duration `yaml:"aggregate_window" mapstructure:"aggregate_window" env:"NOTIFY_AGGREGATE_WINDOW"`
+	AggregateBy     []string      `yaml:"aggregate_by" mapstructure:"aggregate_by"`
 	StopOnFail      bool          `yaml:"stop_on_fail" mapstructure:"stop_on_fail" env:"NOTIFY_STOP_ON_FAIL"`
 	RunOnce         bool          `yaml:"run_once" mapstructure:"run_once" env:"NOTIFY_RUN_ONCE"`
+	SilenceFile     string        `yaml:"silence_file" mapstructure:"silence_file" env:"NOTIFY_SILENCE_FILE"`
+	QueueSize       int           `yaml:"queue_size" mapstructure:"queue_size" env:"NOTIFY_QUEUE_SIZE"`
+	QueueWorkers    int           `yaml:"queue_workers" mapstructure:"queue_workers" env:"NOTIFY_QUEUE_WORKERS"`
+	QueueOverflow   string        `yaml:"queue_overflow" mapstructure:"queue_overflow" env:"NOTIFY_QUEUE_OVERFLOW"`
+	DrainTimeout    time.Duration `yaml:"drain_timeout" mapstructure:"drain_timeout" env:"NOTIFY_DRAIN_TIMEOUT"`
+	RetryAttempts   int           `yaml:"retry_attempts" mapstructure:"retry_attempts" env:"NOTIFY_RETRY_ATTEMPTS"`
+	RetryBaseDelay  time.Duration `yaml:"retry_base_delay" mapstructure:"retry_base_delay" env:"NOTIFY_RETRY_BASE_DELAY"`
+	RetryMaxDelay   time.Duration `yaml:"retry_max_delay" mapstructure:"retry_max_delay" env:"NOTIFY_RETRY_MAX_DELAY"`
+	OutboxFile      string        `yaml:"outbox_file" mapstructure:"outbox_file" env:"NOTIFY_OUTBOX_FILE"`
+	OutboxInterval  time.Duration `yaml:"outbox_interval" mapstructure:"outbox_interval" env:"NOTIFY_OUTBOX_INTERVAL"`
+	OutboxAttempts  int           `yaml:"outbox_max_attempts" mapstructure:"outbox_max_attempts" env:"NOTIFY_OUTBOX_MAX_ATTEMPTS"`
+	DeadLetterFile  string        `yaml:"dead_letter_file" mapstructure:"dead_letter_file" env:"NOTIFY_DEAD_LETTER_FILE"`
+	BreakerFailures int           `yaml:"breaker_failures" mapstructure:"breaker_failures" env:"NOTIFY_BREAKER_FAILURES"`
+	BreakerCooldown time.Duration `yaml:"breaker_cooldown" mapstructure:"breaker_cooldown" env:"NOTIFY_BREAKER_COOLDOWN"`
+}
+
+// SilenceConfig is either a one-off window (start/end, RFC3339) or a recurring
+// one (schedule plus duration).
+type SilenceConfig struct {
+	Name     string        `yaml:"name" mapstructure:"name"`
+	Match    SilenceMatch  `yaml:"match" mapstructure:"match"`
+	Start    string        `yaml:"start" mapstructure:"start"`
+	End      string        `yaml:"end" mapstructure:"end"`
+	Schedule string        `yaml:"schedule" mapstructure:"schedule"`
+	Duration time.Duration `yaml:"duration" mapstructure:"duration"`
+	Comment  string        `yaml:"comment" mapstructure:"comment"`
+}
+
+type SilenceMatch struct {
+	Name   string            `yaml:"name" mapstructure:"name"`
+	Type   string            `yaml:"type" mapstructure:"type"`
+	Status string            `yaml:"status" mapstructure:"status"`
+	Labels map[string]string `yaml:"labels" mapstructure:"labels"`
+}
+
+type SchedulerConfig struct {
+	Workers int `yaml:"workers" mapstructure:"workers" env:"SCHEDULER_WORKERS"`
+}
+
+type StateConfig struct {
+	File string `yaml:"file" mapstructure:"file" env:"STATE_FILE"`
 }
 
 type LogConfig struct {
@@ -3,11 +3,15 @@ package config
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
+	"services-health-check/internal/core/i18n"
+
 	"github.com/kelseyhightower/envconfig"
 	"github.com/spf13/viper"
 )
@@ -37,10 +41,217 @@ func Load(path string) (*Config, error) {
 	applyEnvOverrides(&cfg)
 	applyGlobalOverrides(&cfg)
 	expandDomainEnv(&cfg)
+	if err := validate(&cfg); err != nil {
+		return nil, err
+	}
 
 	return &cfg, nil
 }
 
+// LoadSilences reads a standalone silence file with a top-level `silences` list.
+// It can be edited while healthd is running.
+func LoadSilences(path string) ([]SilenceConfig, error) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data = []byte(os.ExpandEnv(string(data)))
+	if err := v.ReadConfig(bytes.NewBuffer(data)); err != nil {
+		return nil, err
+	}
+	var out struct {
+		Silences []SilenceConfig `mapstructure:"silences"`
+	}
+	if err := v.Unmarshal(&out); err != nil {
+		return nil, err
+	}
+	return out.Silences, nil
+}
+
+func validate(cfg *Config) error {
+	if !i18n.Supported(cfg.Locale) {
+		return fmt.Errorf("unsupported locale: %q", cfg.Locale)
+	}
+	if !validOverflow(cfg.Notify.QueueOverflow) {
+		return fmt.Errorf("unsupported notify.queue_overflow: %q", cfg.Notify.QueueOverflow)
+	}
+	for i, ch := range cfg.Channels {
+		if !i18n.Supported(ch.Locale) {
+			return fmt.Errorf("unsupported locale at channel index %d (name=%q): %q", i, ch.Name, ch.Locale)
+		}
+		if !validOverflow(ch.QueueOverflow) {
+			return fmt.Errorf("unsupported queue_overflow at channel index %d (name=%q): %q", i, ch.Name, ch.QueueOverflow)
+		}
+	}
+	policies := make(map[string]bool)
+	for i, p := range cfg.Policies {
+		if p.FlapWindow > 0 && p.FlapWindow < 3 {
+			return fmt.Errorf("flap_window at policy index %d (name=%q) must be at least 3: %d", i, p.Name, p.FlapWindow)
+		}
+		if p.Name == "" {
+			continue
+		}
+		if policies[p.Name] {
+			return fmt.Errorf("duplicate policy name at index %d: %q", i, p.Name)
+		}
+		policies[p.Name] = true
+	}
+	checks := make(map[string]bool)
+	for _, c := range cfg.Checks {
+		checks[c.Name] = true
+	}
+	for i, c := range cfg.Checks {
+		for k := range c.Labels {
+			if reservedLabel(k) {
+				return fmt.Errorf("reserved label at check index %d (name=%q): %q", i, c.Name, k)
+			}
+		}
+		if c.Policy != "" && !policies[c.Policy] {
+			return fmt.Errorf("unknown policy at check index %d (name=%q): %q", i, c.Name, c.Policy)
+		}
+		for _, dep := range c.DependsOn {
+			if dep == c.Name {
+				return fmt.Errorf("check %q depends on itself", c.Name)
+			}
+			if !checks[dep] {
+				return fmt.Errorf("unknown dependency at check index %d (name=%q): %q", i, c.Name, dep)
+			}
+		}
+	}
+	channels := make(map[string]bool, len(cfg.Channels))
+	for _, ch := range cfg.Channels {
+		channels[ch.Name] = true
+	}
+	defaults := 0
+	for i, r := range cfg.Routes {
+		if r.Match.NameRegex != "" {
+			if _, err := regexp.Compile(r.Match.NameRegex); err != nil {
+				return fmt.Errorf("invalid name_regex at route index %d: %w", i, err)
+			}
+		}
+		if !validStatus(r.Match.MinStatus) {
+			return fmt.Errorf("unsupported min_status at route index %d: %q", i, r.Match.MinStatus)
+		}
+		if r.Default {
+			defaults++
+			if defaults > 1 {
+				return fmt.Errorf("route index %d: only one default route is allowed", i)
+			}
+		}
+		for j, e := range r.Escalations {
+			if e.After <= 0 {
+				return fmt.Errorf("route index %d escalation %d: after must be positive", i, j)
+			}
+			if len(e.To) == 0 {
+				return fmt.Errorf("route index %d escalation %d: to is required", i, j)
+			}
+			for _, name := range e.To {
+				if !channels[name] {
+					return fmt.Errorf("unknown channel at route index %d escalation %d: %q", i, j, name)
+				}
+			}
+		}
+	}
+	if err := validateFallbacks(cfg.Channels); err != nil {
+		return err
+	}
+	return validateDependencyCycles(cfg.Checks)
+}
+
+// validateFallbacks checks that every fallback names another channel and that
+// following fallbacks never leads back to where it started.
+func validateFallbacks(channels []ChannelConfig) error {
+	fallback := make(map[string]string, len(channels))
+	for _, ch := range channels {
+		fallback[ch.Name] = ch.Fallback
+	}
+	for i, ch := range channels {
+		if ch.Fallback == "" {
+			continue
+		}
+		if ch.Fallback == ch.Name {
+			return fmt.Errorf("channel %q falls back to itself", ch.Name)
+		}
+		if _, ok := fallback[ch.Fallback]; !ok {
+			return fmt.Errorf("unknown fallback at channel index %d (name=%q): %q", i, ch.Name, ch.Fallback)
+		}
+		seen := map[string]bool{ch.Name: true}
+		for next := ch.Fallback; next != ""; next = fallback[next] {
+			if seen[next] {
+				return fmt.Errorf("fallback cycle at channel %q", ch.Name)
+			}
+			seen[next] = true
+		}
+	}
+	return nil
+}
+
+// reservedLabel reports whether key is set by healthd itself on events.
+func reservedLabel(key string) bool {
+	switch key {
+	case "status", "previous_status", "reminder", "flapping", "escalation", "suppressed_dependents":
+		return true
+	default:
+		return false
+	}
+}
+
+func validStatus(status string) bool {
+	switch strings.ToUpper(status) {
+	case "", "OK", "WARN", "CRIT", "UNKNOWN":
+		return true
+	default:
+		return false
+	}
+}
+
+func validOverflow(mode string) bool {
+	switch strings.ToLower(mode) {
+	case "", "drop", "block":
+		return true
+	default:
+		return false
+	}
+}
+
+func validateDependencyCycles(items []CheckConfig) error {
+	deps := make(map[string][]string)
+	for _, c := range items {
+		deps[c.Name] = append(deps[c.Name], c.DependsOn...)
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("dependency cycle at check %q", name)
+		case done:
+			return nil
+		}
+		state[name] = visiting
+		for _, dep := range deps[name] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[name] = done
+		return nil
+	}
+	for _, c := range items {
+		if err := visit(c.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func applyEnvOverrides(cfg *Config) {
 	if hasAnyEnv(checkEnvKeys()) {
 		var ec CheckConfig
@@ -143,6 +354,12 @@ func applyCheckOverrides(cfg *Config, ec CheckConfig) {
 	if envNonEmpty("CHECK_MIN_READY") {
 		c.MinReady = ec.MinReady
 	}
+	if envNonEmpty("CHECK_POLICY") {
+		c.Policy = ec.Policy
+	}
+	if envNonEmpty("CHECK_LABELS") {
+		c.Labels = parseLabels(os.Getenv("CHECK_LABELS"))
+	}
 }
 
 func applyPolicyOverrides(cfg *Config, pc PolicyConfig) {
@@ -160,6 +377,27 @@ func applyPolicyOverrides(cfg *Config, pc PolicyConfig) {
 	if envNonEmpty("POLICY_NOTIFY_ON_RECOVERY") {
 		p.NotifyOnRecovery = pc.NotifyOnRecovery
 	}
+	if envNonEmpty("POLICY_FAILURE_THRESHOLD") {
+		p.FailureThreshold = pc.FailureThreshold
+	}
+	if envNonEmpty("POLICY_SUCCESS_THRESHOLD") {
+		p.SuccessThreshold = pc.SuccessThreshold
+	}
+	if envNonEmpty("POLICY_REPEAT_INTERVAL") {
+		p.RepeatInterval = pc.RepeatInterval
+	}
+	if envNonEmpty("POLICY_MAX_REPEATS") {
+		p.MaxRepeats = pc.MaxRepeats
+	}
+	if envNonEmpty("POLICY_FLAP_WINDOW") {
+		p.FlapWindow = pc.FlapWindow
+	}
+	if envNonEmpty("POLICY_FLAP_HIGH_THRESHOLD") {
+		p.FlapHigh = pc.FlapHigh
+	}
+	if envNonEmpty("POLICY_FLAP_LOW_THRESHOLD") {
+		p.FlapLow = pc.FlapLow
+	}
 }
 
 func applyChannelOverrides(cfg *Config, cc ChannelConfig) {
@@ -182,8 +420,30 @@ func applyChannelOverrides(cfg *Config, cc ChannelConfig) {
 	smtpSubjectSet := envNonEmpty("CHANNEL_SMTP_SUBJECT")
 	smtpImplicitSet := envNonEmpty("CHANNEL_SMTP_IMPLICIT_TLS")
 	smtpSkipVerifySet := envNonEmpty("CHANNEL_SMTP_SKIP_VERIFY")
-
-	if !typeSet && !nameSet && !urlSet && !timeoutSet && !usernameSet {
+	pagerDutyKeySet := envNonEmpty("CHANNEL_PAGERDUTY_ROUTING_KEY")
+	telegramTokenSet := envNonEmpty("CHANNEL_TELEGRAM_BOT_TOKEN")
+	telegramChatSet := envNonEmpty("CHANNEL_TELEGRAM_CHAT_ID")
+	telegramModeSet := envNonEmpty("CHANNEL_TELEGRAM_PARSE_MODE")
+	localeSet := envNonEmpty("CHANNEL_LOCALE")
+	methodSet := envNonEmpty("CHANNEL_METHOD")
+	headersSet := envNonEmpty("CHANNEL_HEADERS")
+	titleTemplateSet := envNonEmpty("CHANNEL_TITLE_TEMPLATE")
+	bodyTemplateSet := envNonEmpty("CHANNEL_BODY_TEMPLATE")
+	hmacSecretSet := envNonEmpty("CHANNEL_HMAC_SECRET")
+	hmacHeaderSet := envNonEmpty("CHANNEL_HMAC_HEADER")
+	lineTokenSet := envNonEmpty("CHANNEL_LINE_TOKEN")
+	lineToSet := envNonEmpty("CHANNEL_LINE_TO")
+	lineFlexSet := envNonEmpty("CHANNEL_LINE_FLEX")
+	queueSizeSet := envNonEmpty("CHANNEL_QUEUE_SIZE")
+	queueWorkersSet := envNonEmpty("CHANNEL_QUEUE_WORKERS")
+	queueOverflowSet := envNonEmpty("CHANNEL_QUEUE_OVERFLOW")
+	retryAttemptsSet := envNonEmpty("CHANNEL_RETRY_ATTEMPTS")
+	fallbackSet := envNonEmpty("CHANNEL_FALLBACK")
+	providerSet := pagerDutyKeySet || telegramTokenSet || telegramChatSet || telegramModeSet ||
+		lineTokenSet || lineToSet || lineFlexSet || queueSizeSet || queueWorkersSet || queueOverflowSet || retryAttemptsSet || fallbackSet ||
+		localeSet || methodSet || headersSet || titleTemplateSet || bodyTemplateSet || hmacSecretSet || hmacHeaderSet
+
+	if !typeSet && !nameSet && !urlSet && !timeoutSet && !usernameSet && !providerSet {
 		if !smtpHostSet && !smtpPortSet && !smtpUserSet && !smtpPassSet && !smtpFromSet && !smtpToSet && !smtpSubjectSet && !smtpImplicitSet && !smtpSkipVerifySet {
 			return
 		}
@@ -204,6 +464,27 @@ func applyChannelOverrides(cfg *Config, cc ChannelConfig) {
 	if envNonEmpty("CHANNEL_USERNAME") {
 		ch.Username = cc.Username
 	}
+	if localeSet {
+		ch.Locale = cc.Locale
+	}
+	if methodSet {
+		ch.Method = cc.Method
+	}
+	if headersSet {
+		ch.Headers = cc.Headers
+	}
+	if titleTemplateSet {
+		ch.TitleTemplate = cc.TitleTemplate
+	}
+	if bodyTemplateSet {
+		ch.BodyTemplate = cc.BodyTemplate
+	}
+	if hmacSecretSet {
+		ch.HMACSecret = cc.HMACSecret
+	}
+	if hmacHeaderSet {
+		ch.HMACHeader = cc.HMACHeader
+	}
 	if smtpHostSet {
 		ch.SMTPHost = cc.SMTPHost
 	}
@@ -231,6 +512,42 @@ func applyChannelOverrides(cfg *Config, cc ChannelConfig) {
 	if smtpSkipVerifySet {
 		ch.SMTPSkipVerifyTLS = cc.SMTPSkipVerifyTLS
 	}
+	if pagerDutyKeySet {
+		ch.PagerDutyKey = cc.PagerDutyKey
+	}
+	if telegramTokenSet {
+		ch.TelegramBotToken = cc.TelegramBotToken
+	}
+	if telegramChatSet {
+		ch.TelegramChatID = cc.TelegramChatID
+	}
+	if telegramModeSet {
+		ch.TelegramParseMode = cc.TelegramParseMode
+	}
+	if lineTokenSet {
+		ch.LineToken = cc.LineToken
+	}
+	if lineToSet {
+		ch.LineTo = cc.LineTo
+	}
+	if lineFlexSet {
+		ch.LineFlex = cc.LineFlex
+	}
+	if queueSizeSet {
+		ch.QueueSize = cc.QueueSize
+	}
+	if queueWorkersSet {
+		ch.QueueWorkers = cc.QueueWorkers
+	}
+	if queueOverflowSet {
+		ch.QueueOverflow = cc.QueueOverflow
+	}
+	if retryAttemptsSet {
+		ch.RetryAttempts = cc.RetryAttempts
+	}
+	if fallbackSet {
+		ch.Fallback = cc.Fallback
+	}
 }
 
 func applyRouteOverrides(cfg *Config, rm RouteMatch) {
@@ -242,9 +559,18 @@ func applyRouteOverrides(cfg *Config, rm RouteMatch) {
 	if envNonEmpty("ROUTE_MATCH_NAME") {
 		r.Match.Name = rm.Name
 	}
+	if envNonEmpty("ROUTE_MATCH_NAME_REGEX") {
+		r.Match.NameRegex = rm.NameRegex
+	}
+	if envNonEmpty("ROUTE_MATCH_TYPE") {
+		r.Match.Type = rm.Type
+	}
 	if envNonEmpty("ROUTE_MATCH_STATUS") {
 		r.Match.Status = rm.Status
 	}
+	if envNonEmpty("ROUTE_MATCH_MIN_STATUS") {
+		r.Match.MinStatus = rm.MinStatus
+	}
 }
 
 func applyRouteToOverrides(cfg *Config, raw string) {
@@ -279,22 +605,29 @@ func checkEnvKeys() []string {
 		"CHECK_ADDRESS", "CHECK_SERVER_NAME", "CHECK_WARN_BEFORE", "CHECK_CRIT_BEFORE",
 		"CHECK_DOMAIN", "CHECK_TOKEN", "CHECK_RDAP_BASE_URL", "CHECK_NAMESPACE", "CHECK_LABEL_SELECTOR",
 		"CHECK_RDAP_BASE_URLS", "CHECK_KUBECONFIG", "CHECK_CONTEXT", "CHECK_MIN_READY", "CHECK_SCHEDULE",
-		"CHECK_SKIP_VERIFY",
+		"CHECK_SKIP_VERIFY", "CHECK_POLICY", "CHECK_LABELS",
 	}
 }
 
 func policyEnvKeys() []string {
 	return []string{
 		"POLICY_NAME", "POLICY_COOLDOWN", "POLICY_NOTIFY_ON_RECOVERY",
+		"POLICY_FAILURE_THRESHOLD", "POLICY_SUCCESS_THRESHOLD", "POLICY_REPEAT_INTERVAL", "POLICY_MAX_REPEATS",
+		"POLICY_FLAP_WINDOW", "POLICY_FLAP_HIGH_THRESHOLD", "POLICY_FLAP_LOW_THRESHOLD",
 	}
 }
 
 func channelEnvKeys() []string {
 	return []string{
 		"CHANNEL_TYPE", "CHANNEL_NAME", "CHANNEL_URL", "CHANNEL_TIMEOUT", "CHANNEL_USERNAME",
+		"CHANNEL_LOCALE", "CHANNEL_METHOD", "CHANNEL_HEADERS", "CHANNEL_TITLE_TEMPLATE", "CHANNEL_BODY_TEMPLATE", "CHANNEL_HMAC_SECRET", "CHANNEL_HMAC_HEADER",
 		"CHANNEL_SMTP_HOST", "CHANNEL_SMTP_PORT", "CHANNEL_SMTP_USERNAME", "CHANNEL_SMTP_PASSWORD",
 		"CHANNEL_SMTP_FROM", "CHANNEL_SMTP_TO", "CHANNEL_SMTP_SUBJECT",
 		"CHANNEL_SMTP_IMPLICIT_TLS", "CHANNEL_SMTP_SKIP_VERIFY",
+		"CHANNEL_PAGERDUTY_ROUTING_KEY",
+		"CHANNEL_TELEGRAM_BOT_TOKEN", "CHANNEL_TELEGRAM_CHAT_ID", "CHANNEL_TELEGRAM_PARSE_MODE",
+		"CHANNEL_LINE_TOKEN", "CHANNEL_LINE_TO", "CHANNEL_LINE_FLEX",
+		"CHANNEL_QUEUE_SIZE", "CHANNEL_QUEUE_WORKERS", "CHANNEL_QUEUE_OVERFLOW", "CHANNEL_RETRY_ATTEMPTS", "CHANNEL_FALLBACK",
 	}
 }
 
@@ -317,7 +650,8 @@ func envExists(key string) bool {
 
 func routeEnvKeys() []string {
 	return []string{
-		"ROUTE_MATCH_NAME", "ROUTE_MATCH_STATUS", "ROUTE_TO",
+		"ROUTE_MATCH_NAME", "ROUTE_MATCH_NAME_REGEX", "ROUTE_MATCH_TYPE",
+		"ROUTE_MATCH_STATUS", "ROUTE_MATCH_MIN_STATUS", "ROUTE_TO",
 	}
 }
 
@@ -356,12 +690,88 @@ func applyGlobalOverrides(cfg *Config) {
 			cfg.Notify.AggregateWindow = d
 		}
 	}
+	if envNonEmpty("NOTIFY_AGGREGATE_BY") {
+		cfg.Notify.AggregateBy = parseCSV(os.Getenv("NOTIFY_AGGREGATE_BY"))
+	}
 	if envNonEmpty("NOTIFY_STOP_ON_FAIL") {
 		val := strings.TrimSpace(os.Getenv("NOTIFY_STOP_ON_FAIL"))
 		if val == "1" || strings.EqualFold(val, "true") || strings.EqualFold(val, "yes") {
 			cfg.Notify.StopOnFail = true
 		}
 	}
+	if envNonEmpty("LOCALE") {
+		cfg.Locale = strings.TrimSpace(os.Getenv("LOCALE"))
+	}
+	if envNonEmpty("STATE_FILE") {
+		cfg.State.File = strings.TrimSpace(os.Getenv("STATE_FILE"))
+	}
+	if envNonEmpty("SCHEDULER_WORKERS") {
+		if v, err := strconv.Atoi(strings.TrimSpace(os.Getenv("SCHEDULER_WORKERS"))); err == nil {
+			cfg.Scheduler.Workers = v
+		}
+	}
+	if envNonEmpty("NOTIFY_SILENCE_FILE") {
+		cfg.Notify.SilenceFile = strings.TrimSpace(os.Getenv("NOTIFY_SILENCE_FILE"))
+	}
+	if envNonEmpty("NOTIFY_QUEUE_SIZE") {
+		if v, err := strconv.Atoi(strings.TrimSpace(os.Getenv("NOTIFY_QUEUE_SIZE"))); err == nil {
+			cfg.Notify.QueueSize = v
+		}
+	}
+	if envNonEmpty("NOTIFY_QUEUE_WORKERS") {
+		if v, err := strconv.Atoi(strings.TrimSpace(os.Getenv("NOTIFY_QUEUE_WORKERS"))); err == nil {
+			cfg.Notify.QueueWorkers = v
+		}
+	}
+	if envNonEmpty("NOTIFY_QUEUE_OVERFLOW") {
+		cfg.Notify.QueueOverflow = strings.TrimSpace(os.Getenv("NOTIFY_QUEUE_OVERFLOW"))
+	}
+	if envNonEmpty("NOTIFY_DRAIN_TIMEOUT") {
+		if d, err := time.ParseDuration(os.Getenv("NOTIFY_DRAIN_TIMEOUT")); err == nil {
+			cfg.Notify.DrainTimeout = d
+		}
+	}
+	if envNonEmpty("NOTIFY_RETRY_ATTEMPTS") {
+		if v, err := strconv.Atoi(strings.TrimSpace(os.Getenv("NOTIFY_RETRY_ATTEMPTS"))); err == nil {
+			cfg.Notify.RetryAttempts = v
+		}
+	}
+	if envNonEmpty("NOTIFY_RETRY_BASE_DELAY") {
+		if d, err := time.ParseDuration(os.Getenv("NOTIFY_RETRY_BASE_DELAY")); err == nil {
+			cfg.Notify.RetryBaseDelay = d
+		}
+	}
+	if envNonEmpty("NOTIFY_RETRY_MAX_DELAY") {
+		if d, err := time.ParseDuration(os.Getenv("NOTIFY_RETRY_MAX_DELAY")); err == nil {
+			cfg.Notify.RetryMaxDelay = d
+		}
+	}
+	if envNonEmpty("NOTIFY_OUTBOX_FILE") {
+		cfg.Notify.OutboxFile = strings.TrimSpace(os.Getenv("NOTIFY_OUTBOX_FILE"))
+	}
+	if envNonEmpty("NOTIFY_OUTBOX_INTERVAL") {
+		if d, err := time.ParseDuration(os.Getenv("NOTIFY_OUTBOX_INTERVAL")); err == nil {
+			cfg.Notify.OutboxInterval = d
+		}
+	}
+	if envNonEmpty("NOTIFY_OUTBOX_MAX_ATTEMPTS") {
+		if v, err := strconv.Atoi(strings.TrimSpace(os.Getenv("NOTIFY_OUTBOX_MAX_ATTEMPTS"))); err == nil {
+			cfg.Notify.OutboxAttempts = v
+		}
+	}
+	if envNonEmpty("NOTIFY_DEAD_LETTER_FILE") {
+		cfg.Notify.DeadLetterFile = strings.TrimSpace(os.Getenv("NOTIFY_DEAD_LETTER_FILE"))
+	}
+	if envNonEmpty("NOTIFY_BREAKER_FAILURES") {
+		if v, err := strconv.Atoi(strings.TrimSpace(os.Getenv("NOTIFY_BREAKER_FAILURES"))); err == nil {
+			cfg.Notify.BreakerFailures = v
+		}
+	}
+	if envNonEmpty("NOTIFY_BREAKER_COOLDOWN") {
+		if d, err := time.ParseDuration(os.Getenv("NOTIFY_BREAKER_COOLDOWN")); err == nil {
+			cfg.Notify.BreakerCooldown = d
+		}
+	}
 	if envNonEmpty("NOTIFY_RUN_ONCE") {
 		val := strings.TrimSpace(os.Getenv("NOTIFY_RUN_ONCE"))
 		if val == "1" || strings.EqualFold(val, "true") || strings.EqualFold(val, "yes") {
@@ -386,6 +796,7 @@ func expandDomainEnv(cfg *Config) {
 	var timeout time.Duration
 	var rdapBaseURL string
 	var rdapBaseURLs []string
+	var policy string
 
 	if envNonEmpty("CHECK_DOMAIN_WARN_BEFORE") {
 		if d, err := time.ParseDuration(os.Getenv("CHECK_DOMAIN_WARN_BEFORE")); err == nil {
@@ -412,6 +823,10 @@ func expandDomainEnv(cfg *Config) {
 		rdapBaseURLs = parseCSV(os.Getenv("CHECK_DOMAIN_RDAP_BASE_URLS"))
 	}
 
+	if envNonEmpty("CHECK_DOMAIN_POLICY") {
+		policy = strings.TrimSpace(os.Getenv("CHECK_DOMAIN_POLICY"))
+	}
+
 	if schedule == "" {
 		schedule = "0 3 * * *"
 	}
@@ -433,6 +848,7 @@ func expandDomainEnv(cfg *Config) {
 			Timeout:      timeout,
 			RDAPBaseURL:  rdapBaseURL,
 			RDAPBaseURLs: rdapBaseURLs,
+			Policy:       policy,
 		})
 	}
 }
@@ -445,6 +861,18 @@ func envNonEmpty(key string) bool {
 	return strings.TrimSpace(val) != ""
 }
 
+// parseLabels reads "key=value" pairs separated by commas.
+func parseLabels(input string) map[string]string {
+	out := make(map[string]string)
+	for _, p := range parseCSV(input) {
+		k, v, _ := strings.Cut(p, "=")
+		if k = strings.TrimSpace(k); k != "" {
+			out[k] = strings.TrimSpace(v)
+		}
+	}
+	return out
+}
+
 func parseCSV(input string) []string {
 	parts := strings.Split(input, ",")
 	out := make([]string, 0, len(parts))
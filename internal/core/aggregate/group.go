@@ -0,0 +1,82 @@
+package aggregate
+
+import (
+	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/notify"
+)
+
+// Grouper groups events by check type and the values of the By labels, and
+// counts how many configured checks fall in each group.
+type Grouper struct {
+	By []string
+
+	expected map[string]int
+}
+
+func NewGrouper(by []string) *Grouper {
+	return &Grouper{By: by, expected: make(map[string]int)}
+}
+
+// Key returns the group of a check, e.g. "http/platform" for By [team].
+func (g *Grouper) Key(typ string, labels map[string]string) string {
+	if typ == "" {
+		typ = "unknown"
+	}
+	key := typ
+	for _, k := range g.By {
+		key += "/" + labels[k]
+	}
+	return key
+}
+
+// Expect counts a configured check in its group.
+func (g *Grouper) Expect(typ string, labels map[string]string) {
+	g.expected[g.Key(typ, labels)]++
+}
+
+// Expected returns the number of configured checks in group key.
+func (g *Grouper) Expected(key string) int {
+	return g.expected[key]
+}
+
+// SharedLabels returns the labels every event carries with the same value,
+// leaving out the per-event status labels.
+func SharedLabels(events []notify.Event) map[string]string {
+	if len(events) == 0 {
+		return nil
+	}
+	out := make(map[string]string)
+	for k, v := range events[0].Labels {
+		if k == "status" || k == "previous_status" {
+			continue
+		}
+		out[k] = v
+	}
+	for _, ev := range events[1:] {
+		for k, v := range out {
+			if got, ok := ev.Labels[k]; !ok || got != v {
+				delete(out, k)
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// PreviousStatus returns the most severe previous status of events, so that
+// an aggregate of recoveries still passes the min_status routes its failures
+// went through. It is empty when no event has one.
+func PreviousStatus(events []notify.Event) string {
+	var out string
+	for _, ev := range events {
+		if ev.PreviousStatus == "" {
+			continue
+		}
+		if out == "" || check.Severity(check.Status(ev.PreviousStatus)) > check.Severity(check.Status(out)) {
+			out = ev.PreviousStatus
+		}
+	}
+	return out
+}
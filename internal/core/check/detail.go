@@ -0,0 +1,37 @@
+package check
+
+import "services-health-check/internal/core/i18n"
+
+// DetailKind tells notifiers how to render a detail value.
+type DetailKind string
+
+const (
+	KindText   DetailKind = ""
+	KindDomain DetailKind = "domain"
+	KindPod    DetailKind = "pod"
+	KindURL    DetailKind = "url"
+)
+
+// Detail is one structured item of a check result, such as the expiry date
+// of a certificate or the unready deployments of a cluster. Either Label or
+// Value may be empty: a list of pods only needs values, a note only a label.
+type Detail struct {
+	Label    i18n.Message
+	Value    string
+	Kind     DetailKind
+	Children []Detail
+}
+
+// LocalizeDetails returns a copy of items with every label rendered in locale.
+func LocalizeDetails(items []Detail, locale string) []Detail {
+	if len(items) == 0 {
+		return nil
+	}
+	out := make([]Detail, len(items))
+	for i, d := range items {
+		d.Label = i18n.Text(i18n.Render(locale, d.Label))
+		d.Children = LocalizeDetails(d.Children, locale)
+		out[i] = d
+	}
+	return out
+}
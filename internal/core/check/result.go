@@ -1,6 +1,11 @@
 package check
 
-import "time"
+import (
+	"strings"
+	"time"
+
+	"services-health-check/internal/core/i18n"
+)
 
 type Status string
 
@@ -11,11 +16,30 @@ const (
 	StatusUnknown Status = "UNKNOWN"
 )
 
+// Result is the outcome of one check run. Message is a catalogue key with
+// parameters so that it can be rendered in each channel's locale.
 type Result struct {
 	Name      string
 	Type      string
 	Status    Status
-	Message   string
+	Message   i18n.Message
+	Details   []Detail
 	Metrics   map[string]any
+	Labels    map[string]string
 	CheckedAt time.Time
 }
+
+// Severity orders statuses for thresholds: OK < UNKNOWN < WARN < CRIT.
+// Unrecognised statuses rank with UNKNOWN.
+func Severity(s Status) int {
+	switch Status(strings.ToUpper(string(s))) {
+	case StatusOK:
+		return 0
+	case StatusWarn:
+		return 2
+	case StatusCrit:
+		return 3
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,96 @@
+package escalation
+
+import (
+	"sync"
+	"time"
+
+	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/core/policy"
+)
+
+// Step re-sends a CRIT event to To once it has been open for After.
+type Step struct {
+	After time.Duration
+	To    []string
+}
+
+// Pending is a step waiting for its time.
+type Pending struct {
+	Event notify.Event
+	Step  Step
+	Due   time.Time
+}
+
+// Tracker keeps the escalation steps of open CRIT events per check. Steps are
+// dropped when the check leaves CRIT, either through an event or, when
+// Statuses is set, through the status the policy holds.
+type Tracker struct {
+	Statuses policy.StatusReader
+
+	mu      sync.Mutex
+	pending map[string][]*Pending
+}
+
+func NewTracker(statuses policy.StatusReader) *Tracker {
+	return &Tracker{Statuses: statuses, pending: make(map[string][]*Pending)}
+}
+
+// Track arms steps for an event that opens a CRIT and clears the check on any
+// other status. A CRIT that continues one already open arms nothing.
+func (t *Tracker) Track(event notify.Event, steps []Step) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if event.Status != string(check.StatusCrit) {
+		delete(t.pending, event.Service)
+		return
+	}
+	if event.PreviousStatus == string(check.StatusCrit) || len(t.pending[event.Service]) > 0 {
+		return
+	}
+	for _, step := range steps {
+		t.pending[event.Service] = append(t.pending[event.Service], &Pending{
+			Event: event,
+			Step:  step,
+			Due:   event.OccurredAt.Add(step.After),
+		})
+	}
+}
+
+// Due removes and returns the steps whose time has come at now and whose
+// check is still CRIT. Each step is returned once.
+func (t *Tracker) Due(now time.Time) []Pending {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var out []Pending
+	for service, items := range t.pending {
+		if !t.stillCritical(service) {
+			delete(t.pending, service)
+			continue
+		}
+		rest := items[:0]
+		for _, p := range items {
+			if now.Before(p.Due) {
+				rest = append(rest, p)
+				continue
+			}
+			out = append(out, *p)
+		}
+		if len(rest) == 0 {
+			delete(t.pending, service)
+			continue
+		}
+		t.pending[service] = rest
+	}
+	return out
+}
+
+func (t *Tracker) stillCritical(service string) bool {
+	if t.Statuses == nil {
+		return true
+	}
+	status, ok := t.Statuses.Status(service)
+	return !ok || status == check.StatusCrit
+}
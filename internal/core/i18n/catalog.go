@@ -0,0 +1,160 @@
+package i18n
+
+// catalog holds the format strings per locale. Keys missing from a locale
+// fall back to DefaultLocale.
+var catalog = map[string]map[string]string{
+	ZhTW: {
+		"list.separator": "；",
+
+		"check.request_failed": "請求建立失敗: %s",
+		"check.connect_failed": "連線失敗: %s",
+		"check.decode_failed":  "解析回應失敗: %s",
+
+		"http.status": "HTTP 狀態: %s",
+
+		"ssl.missing_address": "缺少 address",
+		"ssl.dial_failed":     "TLS 連線失敗 %s (SNI %s): %s",
+		"ssl.no_certificate":  "未取得憑證",
+		"ssl.remaining":       "憑證尚有 %s",
+		"ssl.expired":         "憑證已過期",
+		"ssl.expiring":        "憑證即將過期：%s",
+
+		"cloudflare.missing_token":  "缺少 Cloudflare token",
+		"cloudflare.request_failed": "建立請求失敗: %s",
+		"cloudflare.ok":             "token 正常",
+		"cloudflare.verify_failed":  "token 驗證失敗: %s",
+		"cloudflare.status":         "token 狀態: %s",
+
+		"domain.missing":       "缺少 domain",
+		"domain.lookup_failed": "查詢失敗（%s）: %s",
+		"domain.remaining":     "網域尚有 %s（%s）",
+		"domain.expired":       "網域已過期（%s）",
+		"domain.expiring":      "網域即將過期（%s）：%s",
+
+		"k8s.healthy":   "deployments healthy",
+		"k8s.none":      "找不到任何 Deployment",
+		"k8s.min_ready": "就緒數不足：%d（最低 %d）",
+		"k8s.ready":     "就緒 %d/%d",
+
+		"policy.changed":         "%s 狀態：%s → %s",
+		"policy.recovered":       "%s 已恢復（%s → %s）",
+		"policy.recovered_after": "%s 已恢復（%s → %s，持續 %s）",
+		"policy.scan_failed":     "%s 掃描失敗",
+		"policy.reminder":        "%s 仍為 %s（已持續 %s）",
+		"policy.flap_started":    "%s 狀態抖動中（變化率 %.0f%%），暫停個別告警",
+		"policy.flap_stopped":    "%s 狀態已穩定：%s",
+		"policy.dependents":      "%s 仍為 %s，%d 個相依檢查受影響",
+
+		"escalation.summary": "[升級] %s（CRIT 超過 %s 未恢復）",
+
+		"aggregate.summary": "%s 檢查彙總（%d）",
+		"aggregate.none":    "無 WARN/CRIT",
+		"aggregate.counts":  "CRIT %d、WARN %d、UNKNOWN %d、恢復 %d",
+
+		"breaker.open":   "通知管道 %s 連續失敗，已暫停發送",
+		"breaker.closed": "通知管道 %s 已恢復發送",
+
+		"detail.url":          "網址",
+		"detail.http_status":  "HTTP 狀態",
+		"detail.host":         "主機",
+		"detail.address":      "位址",
+		"detail.domain":       "網域",
+		"detail.expires_at":   "到期時間",
+		"detail.remaining":    "剩餘",
+		"detail.token_status": "token 狀態",
+		"detail.token_id":     "token ID",
+		"detail.ready":        "就緒",
+		"detail.unready":      "未就緒",
+		"detail.dependents":   "受影響的相依檢查",
+		"detail.channel":      "通知管道",
+		"detail.error":        "錯誤",
+		"detail.fallback":     "改送至",
+
+		"label.service":   "服務",
+		"label.status":    "狀態",
+		"label.time":      "時間",
+		"label.details":   "細節",
+		"label.incident":  "事件",
+		"label.started":   "開始",
+		"label.resolved":  "解決",
+		"label.dedup":     "去重鍵",
+		"label.truncated": "（已截斷）",
+	},
+	En: {
+		"list.separator": "; ",
+
+		"check.request_failed": "Failed to build request: %s",
+		"check.connect_failed": "Connection failed: %s",
+		"check.decode_failed":  "Failed to parse response: %s",
+
+		"http.status": "HTTP status: %s",
+
+		"ssl.missing_address": "address is missing",
+		"ssl.dial_failed":     "TLS connection to %s failed (SNI %s): %s",
+		"ssl.no_certificate":  "No certificate received",
+		"ssl.remaining":       "Certificate valid for %s",
+		"ssl.expired":         "Certificate has expired",
+		"ssl.expiring":        "Certificate expires soon: %s",
+
+		"cloudflare.missing_token":  "Cloudflare token is missing",
+		"cloudflare.request_failed": "Failed to build request: %s",
+		"cloudflare.ok":             "Token is valid",
+		"cloudflare.verify_failed":  "Token verification failed: %s",
+		"cloudflare.status":         "Token status: %s",
+
+		"domain.missing":       "domain is missing",
+		"domain.lookup_failed": "Lookup failed (%s): %s",
+		"domain.remaining":     "Domain valid for %s (%s)",
+		"domain.expired":       "Domain has expired (%s)",
+		"domain.expiring":      "Domain expires soon (%s): %s",
+
+		"k8s.healthy":   "deployments healthy",
+		"k8s.none":      "No deployments found",
+		"k8s.min_ready": "Not enough ready deployments: %d (minimum %d)",
+		"k8s.ready":     "Ready %d/%d",
+
+		"policy.changed":         "%s status: %s → %s",
+		"policy.recovered":       "%s recovered (%s → %s)",
+		"policy.recovered_after": "%s recovered (%s → %s, lasted %s)",
+		"policy.scan_failed":     "%s check could not run",
+		"policy.reminder":        "%s is still %s (for %s)",
+		"policy.flap_started":    "%s is flapping (%.0f%% state changes), individual alerts paused",
+		"policy.flap_stopped":    "%s has stabilised: %s",
+		"policy.dependents":      "%s is still %s, %d dependent checks affected",
+
+		"escalation.summary": "[Escalated] %s (CRIT for more than %s)",
+
+		"aggregate.summary": "%s check summary (%d)",
+		"aggregate.none":    "No WARN/CRIT",
+		"aggregate.counts":  "CRIT %d, WARN %d, UNKNOWN %d, recovered %d",
+
+		"breaker.open":   "Notification channel %s keeps failing; deliveries are paused",
+		"breaker.closed": "Notification channel %s is delivering again",
+
+		"detail.url":          "URL",
+		"detail.http_status":  "HTTP status",
+		"detail.host":         "Host",
+		"detail.address":      "Address",
+		"detail.domain":       "Domain",
+		"detail.expires_at":   "Expires at",
+		"detail.remaining":    "Remaining",
+		"detail.token_status": "Token status",
+		"detail.token_id":     "Token ID",
+		"detail.ready":        "Ready",
+		"detail.unready":      "Not ready",
+		"detail.dependents":   "Affected dependent checks",
+		"detail.channel":      "Channel",
+		"detail.error":        "Error",
+		"detail.fallback":     "Fallback",
+
+		"label.service":   "Service",
+		"label.status":    "Status",
+		"label.time":      "Time",
+		"label.details":   "Details",
+		"label.incident":  "Incident",
+		"label.started":   "Started",
+		"label.resolved":  "Resolved",
+		"label.dedup":     "Dedup Key",
+		"label.truncated": " (truncated)",
+	},
+}
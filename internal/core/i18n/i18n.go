@@ -0,0 +1,183 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	ZhTW = "zh-TW"
+	En   = "en"
+
+	// DefaultLocale is used when no locale is configured.
+	DefaultLocale = ZhTW
+)
+
+var (
+	mu            sync.RWMutex
+	defaultLocale = DefaultLocale
+)
+
+// Message is a catalogue key plus the arguments for its format string. Args
+// may hold nested Messages or Lists, which are rendered in the same locale.
+// A Message with an empty Key is literal text (see Text).
+type Message struct {
+	Key  string
+	Args []any
+}
+
+// List is rendered as its items joined by the locale's list separator.
+type List []any
+
+// Message wraps the list so it can stand on its own as a message.
+func (l List) Message() Message {
+	return Message{Args: []any{l}}
+}
+
+// M builds a Message for key.
+func M(key string, args ...any) Message {
+	return Message{Key: key, Args: args}
+}
+
+// Text wraps text that must not be translated, such as error strings.
+func Text(s string) Message {
+	return Message{Args: []any{s}}
+}
+
+func (m Message) IsZero() bool {
+	return m.Key == "" && len(m.Args) == 0
+}
+
+// MarshalJSON encodes m as its text in the default locale.
+func (m Message) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON decodes rendered text back into a literal message.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*m = Message{}
+	if s != "" {
+		*m = Text(s)
+	}
+	return nil
+}
+
+// String renders m in the default locale.
+func (m Message) String() string {
+	return Render(Default(), m)
+}
+
+// T renders key with args in locale.
+func T(locale, key string, args ...any) string {
+	return Render(locale, M(key, args...))
+}
+
+// Render formats m with the catalogue of locale, falling back to the default
+// locale and then to the key itself.
+func Render(locale string, m Message) string {
+	loc, ok := Normalize(locale)
+	if !ok {
+		loc = Default()
+	}
+	if m.Key == "" {
+		if len(m.Args) == 0 {
+			return ""
+		}
+		return fmt.Sprint(renderArg(loc, m.Args[0]))
+	}
+
+	format, ok := lookup(loc, m.Key)
+	if !ok {
+		if len(m.Args) == 0 {
+			return m.Key
+		}
+		return m.Key + " " + fmt.Sprint(renderArgs(loc, m.Args)...)
+	}
+	if len(m.Args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, renderArgs(loc, m.Args)...)
+}
+
+func renderArgs(locale string, args []any) []any {
+	out := make([]any, len(args))
+	for i, a := range args {
+		out[i] = renderArg(locale, a)
+	}
+	return out
+}
+
+func renderArg(locale string, arg any) any {
+	switch v := arg.(type) {
+	case Message:
+		return Render(locale, v)
+	case List:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, fmt.Sprint(renderArg(locale, item)))
+		}
+		sep, _ := lookup(locale, "list.separator")
+		return strings.Join(parts, sep)
+	default:
+		return arg
+	}
+}
+
+func lookup(locale, key string) (string, bool) {
+	if format, ok := catalog[locale][key]; ok {
+		return format, true
+	}
+	format, ok := catalog[DefaultLocale][key]
+	return format, ok
+}
+
+// Normalize maps spellings such as "zh_TW", "zh-Hant" or "en-US" to a
+// supported locale.
+func Normalize(locale string) (string, bool) {
+	l := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	switch {
+	case l == "zh" || l == "zh-tw" || l == "zh-hant" || strings.HasPrefix(l, "zh-hant-"):
+		return ZhTW, true
+	case l == "en" || strings.HasPrefix(l, "en-"):
+		return En, true
+	default:
+		return "", false
+	}
+}
+
+// Supported reports whether locale is empty or maps to a catalogue.
+func Supported(locale string) bool {
+	if strings.TrimSpace(locale) == "" {
+		return true
+	}
+	_, ok := Normalize(locale)
+	return ok
+}
+
+// SetDefault changes the locale used by Message.String and by Render when
+// the requested locale is empty or unknown.
+func SetDefault(locale string) error {
+	loc := DefaultLocale
+	if strings.TrimSpace(locale) != "" {
+		var ok bool
+		if loc, ok = Normalize(locale); !ok {
+			return fmt.Errorf("unsupported locale %q", locale)
+		}
+	}
+	mu.Lock()
+	defaultLocale = loc
+	mu.Unlock()
+	return nil
+}
+
+func Default() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return defaultLocale
+}
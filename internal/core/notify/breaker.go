@@ -0,0 +1,155 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 5 * time.Minute
+)
+
+var ErrCircuitOpen = errors.New("notify circuit open")
+
+// BreakerState is the state of a Breaker circuit.
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// Breaker stops sending to Next after Threshold consecutive failures and
+// sends to Fallback instead. After Cooldown one event is let through as a
+// probe: success closes the circuit, failure keeps it open for another
+// Cooldown.
+type Breaker struct {
+	Next      Notifier
+	Fallback  Notifier
+	Threshold int
+	Cooldown  time.Duration
+	// OnStateChange, if set, is called when the circuit opens or closes, with
+	// the error that opened it.
+	OnStateChange func(state BreakerState, err error)
+	// Now returns the current time; NewBreaker sets it to time.Now.
+	Now func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+}
+
+// NewBreaker wraps next. Non-positive threshold and cooldown fall back to
+// the defaults; fallback may be nil.
+func NewBreaker(next, fallback Notifier, threshold int, cooldown time.Duration) *Breaker {
+	if threshold <= 0 {
+		threshold = defaultBreakerThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
+	}
+	return &Breaker{Next: next, Fallback: fallback, Threshold: threshold, Cooldown: cooldown, state: BreakerClosed, Now: time.Now}
+}
+
+func (b *Breaker) Name() string {
+	return b.Next.Name()
+}
+
+// State returns the current state of the circuit.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && b.Now().Sub(b.openedAt) >= b.Cooldown {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+func (b *Breaker) Send(ctx context.Context, event Event) error {
+	if !b.allow() {
+		return b.fallback(ctx, event)
+	}
+
+	err := b.Next.Send(ctx, event)
+	if err != nil && ctx.Err() != nil {
+		// Cut short by shutdown; says nothing about the channel.
+		b.release()
+		return err
+	}
+	if b.record(err) {
+		return b.fallback(ctx, event)
+	}
+	return err
+}
+
+// allow reports whether the event may go to Next. In the open state only one
+// probe is let through once the cooldown has passed.
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerClosed:
+		return true
+	case BreakerOpen:
+		if b.Now().Sub(b.openedAt) < b.Cooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		return true
+	default:
+		return false
+	}
+}
+
+// release undoes allow for a probe whose result is unknown.
+func (b *Breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerHalfOpen {
+		b.state = BreakerOpen
+	}
+}
+
+// record updates the circuit with the result of a send and reports whether
+// the event should go to the fallback because the circuit is open.
+func (b *Breaker) record(err error) bool {
+	b.mu.Lock()
+	var changed BreakerState
+	switch {
+	case err == nil:
+		b.failures = 0
+		if b.state != BreakerClosed {
+			b.state = BreakerClosed
+			changed = BreakerClosed
+		}
+	case b.state == BreakerHalfOpen:
+		b.state = BreakerOpen
+		b.openedAt = b.Now()
+	default:
+		b.failures++
+		if b.failures >= b.Threshold {
+			b.state = BreakerOpen
+			b.openedAt = b.Now()
+			changed = BreakerOpen
+		}
+	}
+	open := b.state == BreakerOpen
+	b.mu.Unlock()
+
+	if changed != "" && b.OnStateChange != nil {
+		b.OnStateChange(changed, err)
+	}
+	return open
+}
+
+func (b *Breaker) fallback(ctx context.Context, event Event) error {
+	if b.Fallback == nil {
+		return ErrCircuitOpen
+	}
+	return b.Fallback.Send(ctx, event)
+}
@@ -0,0 +1,147 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxErrorBody caps how much of an error response is kept in HTTPError.
+const maxErrorBody = 512
+
+// HTTPError is an error response from a channel API.
+type HTTPError struct {
+	Channel    string
+	StatusCode int
+	Body       string
+	// RetryAfter is the delay requested by the server, zero if none.
+	RetryAfter time.Duration
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("%s status %d", e.Channel, e.StatusCode)
+	}
+	return fmt.Sprintf("%s status %d: %s", e.Channel, e.StatusCode, e.Body)
+}
+
+// CheckResponse returns nil for responses below 400, otherwise an *HTTPError
+// with the start of the body and the Retry-After header of resp.
+func CheckResponse(channel string, resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return &HTTPError{
+		Channel:    channel,
+		StatusCode: resp.StatusCode,
+		Body:       strings.TrimSpace(string(raw)),
+		RetryAfter: ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// ParseRetryAfter reads a Retry-After value in seconds or as an HTTP date.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.ParseFloat(value, 64); err == nil {
+		if secs <= 0 {
+			return 0
+		}
+		return time.Duration(secs * float64(time.Second))
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// Transient reports whether a failed send is worth retrying: rate limits,
+// server errors, SMTP 4xx replies and network failures. Bad requests, auth
+// errors and template errors are permanent.
+//
+// An error that joins several, such as the per-recipient failures of a Fanout
+// notifier, is transient if any of them is, also when it is wrapped.
+func Transient(err error) bool {
+	if joined := joinedErrors(err); joined != nil {
+		for _, e := range joined {
+			if Transient(e) {
+				return true
+			}
+		}
+		return false
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		code := httpErr.StatusCode
+		return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500
+	}
+	var smtpErr *textproto.Error
+	if errors.As(err, &smtpErr) {
+		return smtpErr.Code >= 400 && smtpErr.Code < 500
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// RecipientError is the failure of a Fanout notifier to reach one recipient.
+type RecipientError struct {
+	Recipient string
+	Err       error
+}
+
+func (e *RecipientError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Recipient, e.Err)
+}
+
+func (e *RecipientError) Unwrap() error { return e.Err }
+
+// FailedRecipients returns the recipients of the RecipientErrors joined in
+// err, or nil when err is not a per-recipient failure.
+func FailedRecipients(err error) []string {
+	var out []string
+	for _, e := range joinedErrors(err) {
+		var rErr *RecipientError
+		if errors.As(e, &rErr) {
+			out = append(out, rErr.Recipient)
+		}
+	}
+	return out
+}
+
+// joinedErrors follows the single-error wrap chain of err down to an error
+// made of several and returns them, or nil if there is none.
+func joinedErrors(err error) []error {
+	for err != nil {
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			return joined.Unwrap()
+		}
+		err = errors.Unwrap(err)
+	}
+	return nil
+}
+
+// retryAfter returns the delay requested by the server for err, if any.
+func retryAfter(err error) time.Duration {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.RetryAfter
+	}
+	return 0
+}
@@ -1,13 +1,85 @@
 package notify
 
-import "time"
+import (
+	"time"
 
+	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/i18n"
+)
+
+// Event is one notification. IncidentID stays the same from the event that
+// opens a failure to the one that resolves it; DedupKey is stable per check.
+//
+// Summary and Details hold the text in the default locale. When SummaryMsg or
+// DetailsMsg is set, Localize re-renders them for a channel's locale. Items
+// are the structured details of the check result.
 type Event struct {
-	Service    string
-	Type       string
-	Status     string
-	Summary    string
-	Details    string
-	Labels     map[string]string
-	OccurredAt time.Time
+	Service        string
+	Type           string
+	Status         string
+	PreviousStatus string
+	Summary        string
+	Details        string
+	SummaryMsg     i18n.Message `json:"-"`
+	DetailsMsg     i18n.Message `json:"-"`
+	Items          []check.Detail
+	Locale         string
+	Labels         map[string]string
+	IncidentID     string
+	DedupKey       string
+	StartedAt      time.Time
+	ResolvedAt     time.Time
+	OccurredAt     time.Time
+
+	// sourceItems are the Items before Localize rendered their labels, so
+	// that a localized event can be localized again for another channel.
+	sourceItems []check.Detail
+}
+
+// SetSummary sets the summary message and its default-locale text.
+func (e *Event) SetSummary(m i18n.Message) {
+	e.SummaryMsg = m
+	e.Summary = m.String()
+}
+
+// SetDetails sets the details message and its default-locale text.
+func (e *Event) SetDetails(m i18n.Message) {
+	e.DetailsMsg = m
+	e.Details = m.String()
+}
+
+// SummaryMessage returns the summary as a message, wrapping plain text.
+func (e Event) SummaryMessage() i18n.Message {
+	if e.SummaryMsg.IsZero() {
+		return i18n.Text(e.Summary)
+	}
+	return e.SummaryMsg
+}
+
+// DetailsMessage returns the details as a message, wrapping plain text.
+func (e Event) DetailsMessage() i18n.Message {
+	if e.DetailsMsg.IsZero() {
+		return i18n.Text(e.Details)
+	}
+	return e.DetailsMsg
+}
+
+// Localize returns a copy of the event rendered in locale.
+func (e Event) Localize(locale string) Event {
+	loc, ok := i18n.Normalize(locale)
+	if !ok {
+		loc = i18n.Default()
+	}
+	e.Locale = loc
+	if !e.SummaryMsg.IsZero() {
+		e.Summary = i18n.Render(loc, e.SummaryMsg)
+	}
+	if !e.DetailsMsg.IsZero() {
+		e.Details = i18n.Render(loc, e.DetailsMsg)
+	}
+	if e.sourceItems == nil {
+		e.sourceItems = e.Items
+	}
+	e.Items = check.LocalizeDetails(e.sourceItems, loc)
+	return e
 }
@@ -0,0 +1,197 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultQueueSize    = 100
+	defaultQueueWorkers = 1
+	// abandonGrace is how long Close waits, after giving up on delivery, for
+	// the workers to hand the remaining events to OnResult.
+	abandonGrace = time.Second
+)
+
+// Overflow modes for a full Queue.
+const (
+	OverflowDrop  = "drop"
+	OverflowBlock = "block"
+)
+
+var (
+	ErrQueueFull   = errors.New("notify queue full")
+	ErrQueueClosed = errors.New("notify queue closed")
+)
+
+// QueueStats is a snapshot of the counters of a Queue.
+type QueueStats struct {
+	Depth     int
+	Enqueued  uint64
+	Delivered uint64
+	Failed    uint64
+	Dropped   uint64
+	Blocked   uint64
+}
+
+// Queue delivers events to Next from a bounded buffer with its own workers, so
+// a slow channel only delays itself. When the buffer is full, Send drops the
+// event (OverflowDrop) or waits for room until ctx ends (OverflowBlock).
+type Queue struct {
+	Next     Notifier
+	Overflow string
+	// OnResult, if set, is called by a worker after every delivery attempt.
+	OnResult func(event Event, err error)
+
+	events  chan Event
+	closing chan struct{}
+	once    sync.Once
+	mu      sync.RWMutex
+	closed  bool
+	workers sync.WaitGroup
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	enqueued  atomic.Uint64
+	delivered atomic.Uint64
+	failed    atomic.Uint64
+	dropped   atomic.Uint64
+	blocked   atomic.Uint64
+}
+
+// NewQueue starts workers goroutines that deliver to next. Non-positive size
+// and workers fall back to the defaults.
+func NewQueue(next Notifier, size, workers int, overflow string) *Queue {
+	if size <= 0 {
+		size = defaultQueueSize
+	}
+	if workers <= 0 {
+		workers = defaultQueueWorkers
+	}
+	if overflow == "" {
+		overflow = OverflowDrop
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &Queue{
+		Next:     next,
+		Overflow: overflow,
+		events:   make(chan Event, size),
+		closing:  make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	q.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go q.work()
+	}
+	return q
+}
+
+func (q *Queue) Name() string {
+	return q.Next.Name()
+}
+
+// Send enqueues event and returns without waiting for delivery. ctx only
+// bounds the wait for room in OverflowBlock mode; delivery runs on the
+// context of the queue, which lives until Close.
+func (q *Queue) Send(ctx context.Context, event Event) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	select {
+	case q.events <- event:
+		q.enqueued.Add(1)
+		return nil
+	default:
+	}
+	if q.Overflow != OverflowBlock {
+		q.dropped.Add(1)
+		return ErrQueueFull
+	}
+
+	q.blocked.Add(1)
+	select {
+	case q.events <- event:
+		q.enqueued.Add(1)
+		return nil
+	case <-q.closing:
+		q.dropped.Add(1)
+		return ErrQueueClosed
+	case <-ctx.Done():
+		q.dropped.Add(1)
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting events and waits for the workers to deliver what is
+// left. Once ctx ends, in-flight sends are cancelled, the remaining events
+// are counted as dropped and passed to OnResult with the cancellation error,
+// and ctx.Err() is returned.
+func (q *Queue) Close(ctx context.Context) error {
+	q.once.Do(func() {
+		close(q.closing)
+		q.mu.Lock()
+		q.closed = true
+		close(q.events)
+		q.mu.Unlock()
+	})
+
+	drained := make(chan struct{})
+	go func() {
+		q.workers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		select {
+		case <-drained:
+		case <-time.After(abandonGrace):
+		}
+		return ctx.Err()
+	}
+}
+
+// Stats returns the current depth and counters of the queue.
+func (q *Queue) Stats() QueueStats {
+	return QueueStats{
+		Depth:     len(q.events),
+		Enqueued:  q.enqueued.Load(),
+		Delivered: q.delivered.Load(),
+		Failed:    q.failed.Load(),
+		Dropped:   q.dropped.Load(),
+		Blocked:   q.blocked.Load(),
+	}
+}
+
+func (q *Queue) work() {
+	defer q.workers.Done()
+	for event := range q.events {
+		if err := q.ctx.Err(); err != nil {
+			q.dropped.Add(1)
+			if q.OnResult != nil {
+				q.OnResult(event, err)
+			}
+			continue
+		}
+		err := q.Next.Send(q.ctx, event)
+		if err != nil {
+			q.failed.Add(1)
+		} else {
+			q.delivered.Add(1)
+		}
+		if q.OnResult != nil {
+			q.OnResult(event, err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	defaultRetryAttempts  = 3
+	defaultRetryBaseDelay = time.Second
+	defaultRetryMaxDelay  = 30 * time.Second
+)
+
+// Retry re-sends an event to Next while the error is Transient. The delay
+// doubles from BaseDelay up to MaxDelay with jitter; a Retry-After from the
+// server replaces it, and one longer than MaxDelay ends the retries so the
+// event can wait in the outbox instead.
+type Retry struct {
+	Next      Notifier
+	Attempts  int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// OnRetry, if set, is called before waiting for the next attempt.
+	OnRetry func(attempt int, wait time.Duration, err error)
+}
+
+// NewRetry wraps next. Non-positive values fall back to the defaults; one
+// attempt disables retries.
+func NewRetry(next Notifier, attempts int, baseDelay, maxDelay time.Duration) *Retry {
+	if attempts <= 0 {
+		attempts = defaultRetryAttempts
+	}
+	if baseDelay <= 0 {
+		baseDelay = defaultRetryBaseDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+	if maxDelay < baseDelay {
+		maxDelay = baseDelay
+	}
+	return &Retry{Next: next, Attempts: attempts, BaseDelay: baseDelay, MaxDelay: maxDelay}
+}
+
+func (r *Retry) Name() string {
+	return r.Next.Name()
+}
+
+// Fanout is implemented by notifiers that deliver one event to several
+// recipients. Retry passes its Do to SendEach so that every recipient is
+// retried on its own and those already reached do not get the event twice.
+type Fanout interface {
+	Notifier
+	SendEach(ctx context.Context, event Event, do func(context.Context, func(context.Context) error) error) error
+}
+
+type recipientsKey struct{}
+
+// WithRecipients limits a Fanout notifier to the given recipients for sends
+// made with the returned context, e.g. to re-send only to those that failed.
+func WithRecipients(ctx context.Context, recipients []string) context.Context {
+	if len(recipients) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, recipientsKey{}, recipients)
+}
+
+// Recipients returns the recipients set by WithRecipients, or nil for all.
+func Recipients(ctx context.Context) []string {
+	recipients, _ := ctx.Value(recipientsKey{}).([]string)
+	return recipients
+}
+
+func (r *Retry) Send(ctx context.Context, event Event) error {
+	if f, ok := r.Next.(Fanout); ok {
+		return f.SendEach(ctx, event, r.Do)
+	}
+	return r.Do(ctx, func(ctx context.Context) error {
+		return r.Next.Send(ctx, event)
+	})
+}
+
+// Do calls send until it succeeds, fails with a permanent error or runs out
+// of attempts.
+func (r *Retry) Do(ctx context.Context, send func(context.Context) error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = send(ctx)
+		if err == nil || attempt >= r.Attempts || ctx.Err() != nil || !Transient(err) {
+			return err
+		}
+
+		wait := r.backoff(attempt)
+		if after := retryAfter(err); after > 0 {
+			if after > r.MaxDelay {
+				// Retrying any sooner would only be refused again.
+				return err
+			}
+			wait = after
+		}
+		if r.OnRetry != nil {
+			r.OnRetry(attempt, wait, err)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+// backoff returns a delay between half and all of BaseDelay*2^(attempt-1),
+// capped at MaxDelay.
+func (r *Retry) backoff(attempt int) time.Duration {
+	d := r.BaseDelay
+	for i := 1; i < attempt && d < r.MaxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, r.MaxDelay)
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package outbox
+
+// lockFile is a no-op where flock is not available; the store is then only
+// safe within one process.
+func lockFile(path string) (func(), error) {
+	return func() {}, nil
+}
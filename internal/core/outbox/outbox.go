@@ -0,0 +1,322 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"services-health-check/internal/core/notify"
+)
+
+const defaultMaxAttempts = 10
+
+// Outbox keeps notifications that still failed after the retries of their
+// channel and re-sends them on Flush. An entry moves to the dead-letter store
+// once it has failed MaxAttempts times or failed with a permanent error.
+type Outbox struct {
+	Pending     *Store
+	DeadLetter  *Store
+	MaxAttempts int
+	Notifiers   map[string]notify.Notifier
+	// OnResult, if set, is called after every re-send.
+	OnResult func(e Entry, err error)
+
+	mu sync.Mutex
+
+	// latest is the OccurredAt of the newest event delivered live per
+	// channel and dedup key; older pending entries for the key are stale.
+	// oldest is the OccurredAt of the oldest pending entry per key, loaded
+	// from Pending on first use, so that Delivered only touches the file when
+	// an entry is actually superseded.
+	stateMu sync.Mutex
+	latest  map[string]time.Time
+	oldest  map[string]time.Time
+}
+
+// FlushResult counts the outcome of a Flush or Replay.
+type FlushResult struct {
+	Sent       int
+	Failed     int
+	Dead       int
+	Superseded int
+}
+
+// New returns an outbox that re-sends through notifiers. A non-positive
+// maxAttempts falls back to the default.
+func New(pending, deadLetter *Store, maxAttempts int, notifiers map[string]notify.Notifier) *Outbox {
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	return &Outbox{Pending: pending, DeadLetter: deadLetter, MaxAttempts: maxAttempts, Notifiers: notifiers}
+}
+
+// Add stores a failed notification. Transient failures, including events
+// dropped by a full queue or cut short by shutdown, wait for the next Flush;
+// permanent ones go straight to the dead-letter store.
+func (o *Outbox) Add(channel string, event notify.Event, err error) error {
+	e := NewEntry(channel, event, err, time.Now())
+	if !retryable(err) {
+		return o.DeadLetter.Append(e)
+	}
+	if err := o.Pending.Append(e); err != nil {
+		return err
+	}
+	o.stateMu.Lock()
+	if o.oldest != nil {
+		o.track(e)
+	}
+	o.stateMu.Unlock()
+	return nil
+}
+
+// Delivered records that event reached channel outside the outbox. Pending
+// entries of the channel with the same DedupKey that are not newer are
+// dropped, so a stored CRIT is not re-sent after the live recovery.
+func (o *Outbox) Delivered(channel string, event notify.Event) error {
+	if event.DedupKey == "" {
+		return nil
+	}
+	if err := o.loadIndex(); err != nil {
+		return err
+	}
+	k := key(channel, event.DedupKey)
+	o.stateMu.Lock()
+	if o.latest == nil {
+		o.latest = make(map[string]time.Time)
+	}
+	if event.OccurredAt.After(o.latest[k]) {
+		o.latest[k] = event.OccurredAt
+	}
+	oldest, ok := o.oldest[k]
+	stale := ok && !oldest.After(o.latest[k])
+	o.stateMu.Unlock()
+	if !stale {
+		return nil
+	}
+	return o.Pending.Update(func(current []Entry) []Entry {
+		out := current[:0]
+		for _, e := range current {
+			if !o.superseded(e) {
+				out = append(out, e)
+			}
+		}
+		o.reindex(out)
+		return out
+	})
+}
+
+// loadIndex reads the pending entries once to build oldest.
+func (o *Outbox) loadIndex() error {
+	o.stateMu.Lock()
+	loaded := o.oldest != nil
+	o.stateMu.Unlock()
+	if loaded {
+		return nil
+	}
+	entries, err := o.Pending.Load()
+	if err != nil {
+		return err
+	}
+	o.reindex(entries)
+	return nil
+}
+
+// reindex rebuilds oldest from the pending entries.
+func (o *Outbox) reindex(entries []Entry) {
+	o.stateMu.Lock()
+	defer o.stateMu.Unlock()
+	o.oldest = make(map[string]time.Time)
+	for _, e := range entries {
+		o.track(e)
+	}
+}
+
+// track adds e to oldest; stateMu must be held.
+func (o *Outbox) track(e Entry) {
+	if e.Event.DedupKey == "" {
+		return
+	}
+	k := key(e.Channel, e.Event.DedupKey)
+	if at, ok := o.oldest[k]; !ok || e.Event.OccurredAt.Before(at) {
+		o.oldest[k] = e.Event.OccurredAt
+	}
+}
+
+func key(channel, dedupKey string) string {
+	return channel + "\x00" + dedupKey
+}
+
+// superseded reports whether a newer event with the same dedup key has
+// already been delivered to the channel of e.
+func (o *Outbox) superseded(e Entry) bool {
+	if e.Event.DedupKey == "" {
+		return false
+	}
+	o.stateMu.Lock()
+	defer o.stateMu.Unlock()
+	latest, ok := o.latest[key(e.Channel, e.Event.DedupKey)]
+	return ok && !e.Event.OccurredAt.After(latest)
+}
+
+// Flush re-sends every pending entry once, in order. After a transient
+// failure the remaining entries of that channel wait for the next Flush;
+// entries superseded by a newer delivered event are dropped unsent.
+func (o *Outbox) Flush(ctx context.Context) (FlushResult, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var res FlushResult
+	entries, err := o.Pending.Load()
+	if err != nil || len(entries) == 0 {
+		return res, err
+	}
+
+	removed := make(map[string]bool)
+	updated := make(map[string]Entry)
+	var dead []Entry
+	blocked := make(map[string]bool)
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+		if blocked[e.Channel] {
+			continue
+		}
+		if o.superseded(e) {
+			removed[e.ID] = true
+			res.Superseded++
+			continue
+		}
+		err := o.send(ctx, e)
+		if err == nil {
+			removed[e.ID] = true
+			res.Sent++
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		e = failed(e, err)
+		if e.Attempts >= o.MaxAttempts || !retryable(err) {
+			dead = append(dead, e)
+			res.Dead++
+			continue
+		}
+		updated[e.ID] = e
+		blocked[e.Channel] = true
+		res.Failed++
+	}
+
+	if err := o.DeadLetter.Append(dead...); err != nil {
+		return res, err
+	}
+	for _, e := range dead {
+		removed[e.ID] = true
+	}
+	keep := rewrite(removed, updated)
+	return res, o.Pending.Update(func(current []Entry) []Entry {
+		out := keep(current)
+		o.reindex(out)
+		return out
+	})
+}
+
+// Replay re-sends the dead-letter entries with the given IDs, or all of them
+// when ids is empty. Delivered entries leave the dead-letter store; the others
+// stay with their new error.
+func (o *Outbox) Replay(ctx context.Context, ids []string) (FlushResult, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var res FlushResult
+	entries, err := o.DeadLetter.Load()
+	if err != nil {
+		return res, err
+	}
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+	for _, id := range ids {
+		if !containsID(entries, id) {
+			return res, fmt.Errorf("dead-letter entry %q not found", id)
+		}
+	}
+
+	removed := make(map[string]bool)
+	updated := make(map[string]Entry)
+	for _, e := range entries {
+		if len(want) > 0 && !want[e.ID] {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		if err := o.send(ctx, e); err != nil {
+			updated[e.ID] = failed(e, err)
+			res.Failed++
+			continue
+		}
+		removed[e.ID] = true
+		res.Sent++
+	}
+	return res, o.DeadLetter.Update(rewrite(removed, updated))
+}
+
+func (o *Outbox) send(ctx context.Context, e Entry) error {
+	var err error
+	n, ok := o.Notifiers[e.Channel]
+	if !ok {
+		err = fmt.Errorf("unknown channel %q", e.Channel)
+	} else {
+		err = n.Send(notify.WithRecipients(ctx, e.Recipients), e.Event)
+	}
+	if o.OnResult != nil {
+		o.OnResult(e, err)
+	}
+	return err
+}
+
+// rewrite drops the removed entries and swaps in the updated ones, keeping
+// entries appended since they were loaded.
+func rewrite(removed map[string]bool, updated map[string]Entry) func([]Entry) []Entry {
+	return func(current []Entry) []Entry {
+		out := make([]Entry, 0, len(current))
+		for _, e := range current {
+			if removed[e.ID] {
+				continue
+			}
+			if u, ok := updated[e.ID]; ok {
+				e = u
+			}
+			out = append(out, e)
+		}
+		return out
+	}
+}
+
+func failed(e Entry, err error) Entry {
+	if recipients := notify.FailedRecipients(err); len(recipients) > 0 {
+		e.Recipients = recipients
+	}
+	e.Attempts++
+	e.LastError = errorText(err)
+	e.LastFailedAt = time.Now()
+	return e
+}
+
+func retryable(err error) bool {
+	return notify.Transient(err) || errors.Is(err, notify.ErrQueueFull) || errors.Is(err, notify.ErrCircuitOpen) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func containsID(entries []Entry, id string) bool {
+	for _, e := range entries {
+		if e.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package outbox
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"services-health-check/internal/core/notify"
+)
+
+// Entry is a notification that could not be delivered to Channel. Event is
+// stored as sent, already rendered in the locale of the channel. Recipients
+// lists the recipients still to be reached when the channel sends to several
+// and only some failed; it is empty when the whole send failed.
+type Entry struct {
+	ID            string       `json:"id"`
+	Channel       string       `json:"channel"`
+	Event         notify.Event `json:"event"`
+	Recipients    []string     `json:"recipients,omitempty"`
+	Attempts      int          `json:"attempts"`
+	LastError     string       `json:"last_error"`
+	FirstFailedAt time.Time    `json:"first_failed_at"`
+	LastFailedAt  time.Time    `json:"last_failed_at"`
+}
+
+// NewEntry records the first failure of event on channel.
+func NewEntry(channel string, event notify.Event, err error, now time.Time) Entry {
+	return Entry{
+		ID:            newID(now),
+		Channel:       channel,
+		Event:         event,
+		Recipients:    notify.FailedRecipients(err),
+		Attempts:      1,
+		LastError:     errorText(err),
+		FirstFailedAt: now,
+		LastFailedAt:  now,
+	}
+}
+
+// Store keeps entries in a JSONL file, one entry per line. Appends add a
+// line; updates rewrite the file atomically. Every access holds a flock on
+// Path+".lock", so the daemon and the deadletter command do not lose each
+// other's writes.
+type Store struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func NewStore(path string) *Store {
+	return &Store{Path: path}
+}
+
+// Load returns all entries. A missing file is an empty store.
+func (s *Store) Load() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := os.Stat(filepath.Dir(s.Path)); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	unlock, err := lockFile(s.Path + ".lock")
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return s.loadLocked()
+}
+
+// Append adds entries to the end of the file.
+func (s *Store) Append(entries ...Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	data, err := encode(entries)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Update replaces the entries with the result of fn, read and written under
+// the locks so that concurrent appends, also from other processes, are not
+// lost.
+func (s *Store) Update(fn func([]Entry) []Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := s.loadLocked()
+	if err != nil {
+		return err
+	}
+	data, err := encode(fn(entries))
+	if err != nil {
+		return err
+	}
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
+
+func (s *Store) loadLocked() ([]Entry, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", s.Path, line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// lock creates the directory of the store and takes the file lock.
+func (s *Store) lock() (func(), error) {
+	if err := s.ensureDir(); err != nil {
+		return nil, err
+	}
+	return lockFile(s.Path + ".lock")
+}
+
+func (s *Store) ensureDir() error {
+	if dir := filepath.Dir(s.Path); dir != "" {
+		return os.MkdirAll(dir, 0o755)
+	}
+	return nil
+}
+
+func encode(entries []Entry) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func newID(now time.Time) string {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return now.Format("20060102T150405.000000000")
+	}
+	return now.Format("20060102T150405") + "-" + hex.EncodeToString(buf)
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
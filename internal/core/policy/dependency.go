@@ -0,0 +1,148 @@
+package policy
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/i18n"
+	"services-health-check/internal/core/notify"
+)
+
+// DependencyPolicy holds back non-OK results of a check while one of its
+// parents is in a notified non-OK state. Those checks never reach Next, so
+// they alert on their own only if they are still failing after the parent
+// recovers. Suppressed dependents are folded into the parent's incident: a
+// newly suppressed dependent yields an update of the parent's last event.
+type DependencyPolicy struct {
+	Next Policy
+
+	parents map[string][]string
+
+	mu         sync.Mutex
+	status     map[string]check.Status
+	suppressed map[string]map[string]bool
+	open       map[string]notify.Event
+}
+
+func NewDependencyPolicy(next Policy, parents map[string][]string) *DependencyPolicy {
+	return &DependencyPolicy{
+		Next:       next,
+		parents:    parents,
+		status:     make(map[string]check.Status),
+		suppressed: make(map[string]map[string]bool),
+		open:       make(map[string]notify.Event),
+	}
+}
+
+func (p *DependencyPolicy) Evaluate(ctx context.Context, res check.Result) (*notify.Event, error) {
+	p.mu.Lock()
+	p.status[res.Name] = res.Status
+	known := make(map[string]bool)
+	for parent, deps := range p.suppressed {
+		if deps[res.Name] {
+			known[parent] = true
+		}
+		delete(deps, res.Name)
+	}
+	if res.Status != check.StatusOK {
+		if parent, ok := p.failingParent(res.Name); ok {
+			if p.suppressed[parent] == nil {
+				p.suppressed[parent] = make(map[string]bool)
+			}
+			p.suppressed[parent][res.Name] = true
+			var update *notify.Event
+			if !known[parent] {
+				update = p.update(parent)
+			}
+			p.mu.Unlock()
+			return update, nil
+		}
+	}
+	p.mu.Unlock()
+
+	event, err := p.Next.Evaluate(ctx, res)
+	if event == nil {
+		return event, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if event.Status == string(check.StatusOK) {
+		delete(p.open, res.Name)
+		return event, err
+	}
+	base := *event
+	base.Type = res.Type
+	p.open[res.Name] = base
+	withDependents(event, sortedKeys(p.suppressed[res.Name]))
+	return event, err
+}
+
+func (p *DependencyPolicy) Status(name string) (check.Status, bool) {
+	if sr, ok := p.Next.(StatusReader); ok {
+		return sr.Status(name)
+	}
+	return "", false
+}
+
+// update re-sends the last event of parent's open incident with the current
+// list of suppressed dependents, or returns nil when none was sent.
+func (p *DependencyPolicy) update(parent string) *notify.Event {
+	last, ok := p.open[parent]
+	if !ok {
+		return nil
+	}
+	deps := sortedKeys(p.suppressed[parent])
+	event := last
+	event.PreviousStatus = last.Status
+	event.SetSummary(i18n.M("policy.dependents", parent, last.Status, len(deps)))
+	event.OccurredAt = time.Now()
+	withDependents(&event, deps)
+	return &event
+}
+
+// failingParent returns a parent whose non-OK state has been notified. When
+// Next cannot report it, the parent's latest result is used.
+func (p *DependencyPolicy) failingParent(name string) (string, bool) {
+	sr, notified := p.Next.(StatusReader)
+	for _, parent := range p.parents[name] {
+		st, ok := p.status[parent]
+		if notified {
+			st, ok = sr.Status(parent)
+		}
+		if ok && st != check.StatusOK {
+			return parent, true
+		}
+	}
+	return "", false
+}
+
+func withDependents(event *notify.Event, deps []string) {
+	if len(deps) == 0 {
+		return
+	}
+	item := check.Detail{Label: i18n.M("detail.dependents")}
+	for _, d := range deps {
+		item.Children = append(item.Children, check.Detail{Value: d})
+	}
+	event.Items = append(append([]check.Detail(nil), event.Items...), item)
+	labels := make(map[string]string, len(event.Labels)+1)
+	for k, v := range event.Labels {
+		labels[k] = v
+	}
+	labels["suppressed_dependents"] = strings.Join(deps, ",")
+	event.Labels = labels
+}
+
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
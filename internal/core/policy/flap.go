@@ -0,0 +1,81 @@
+package policy
+
+import "services-health-check/internal/core/check"
+
+const (
+	defaultFlapHigh = 50.0
+	defaultFlapLow  = 25.0
+)
+
+type flapChange int
+
+const (
+	flapNone flapChange = iota
+	flapStarted
+	flapStopped
+)
+
+// FlapDetector works like Nagios flap detection: it keeps the last Window
+// results of a check, weighs recent status changes more than older ones and
+// marks the check as flapping when the change rate (in percent) rises above
+// High. Flapping ends once the rate drops below Low.
+type FlapDetector struct {
+	Window int
+	High   float64
+	Low    float64
+}
+
+func NewFlapDetector(window int, high, low float64) *FlapDetector {
+	if high <= 0 {
+		high = defaultFlapHigh
+	}
+	if low <= 0 || low > high {
+		low = defaultFlapLow
+		if low > high {
+			low = high
+		}
+	}
+	return &FlapDetector{Window: window, High: high, Low: low}
+}
+
+func (d *FlapDetector) observe(st *State, status check.Status) (flapChange, float64) {
+	st.History = append(st.History, status)
+	if len(st.History) > d.Window {
+		st.History = st.History[len(st.History)-d.Window:]
+	}
+	if len(st.History) < d.Window || d.Window < 3 {
+		return flapNone, 0
+	}
+
+	rate := changeRate(st.History)
+	switch {
+	case !st.Flapping && rate >= d.High:
+		st.Flapping = true
+		return flapStarted, rate
+	case st.Flapping && rate < d.Low:
+		st.Flapping = false
+		return flapStopped, rate
+	}
+	return flapNone, rate
+}
+
+// changeRate returns the weighted share of status changes in history, with
+// weights rising linearly from 0.8 (oldest) to 1.2 (newest).
+func changeRate(history []check.Status) float64 {
+	pairs := len(history) - 1
+	var changed, total float64
+	for i := 1; i < len(history); i++ {
+		weight := 0.8
+		if pairs > 1 {
+			weight += 0.4 * float64(i-1) / float64(pairs-1)
+		}
+		total += weight
+		if history[i] != history[i-1] {
+			changed += weight
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return changed / total * 100
+}
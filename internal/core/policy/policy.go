@@ -10,3 +10,8 @@ import (
 type Policy interface {
 	Evaluate(ctx context.Context, res check.Result) (*notify.Event, error)
 }
+
+// StatusReader reports the alert status a policy currently holds for a check.
+type StatusReader interface {
+	Status(name string) (check.Status, bool)
+}
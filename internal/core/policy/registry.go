@@ -0,0 +1,45 @@
+package policy
+
+import (
+	"context"
+
+	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/notify"
+)
+
+// Registry hands each result to the policy bound to its check, falling back
+// to Default for unbound checks.
+type Registry struct {
+	Default Policy
+
+	byCheck map[string]Policy
+}
+
+func NewRegistry(def Policy) *Registry {
+	return &Registry{
+		Default: def,
+		byCheck: make(map[string]Policy),
+	}
+}
+
+func (r *Registry) Bind(checkName string, p Policy) {
+	r.byCheck[checkName] = p
+}
+
+func (r *Registry) For(checkName string) Policy {
+	if p, ok := r.byCheck[checkName]; ok {
+		return p
+	}
+	return r.Default
+}
+
+func (r *Registry) Evaluate(ctx context.Context, res check.Result) (*notify.Event, error) {
+	return r.For(res.Name).Evaluate(ctx, res)
+}
+
+func (r *Registry) Status(name string) (check.Status, bool) {
+	if sr, ok := r.For(name).(StatusReader); ok {
+		return sr.Status(name)
+	}
+	return "", false
+}
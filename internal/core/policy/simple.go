@@ -2,59 +2,293 @@ package policy
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"reflect"
+	"strconv"
 	"sync"
 	"time"
 
 	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/i18n"
 	"services-health-check/internal/core/notify"
 )
 
+// SimplePolicy emits an event only when a check changes status. A check
+// enters a failure state after FailureThreshold consecutive non-OK results
+// and recovers after SuccessThreshold consecutive OK results.
 type SimplePolicy struct {
 	Cooldown         time.Duration
 	NotifyOnRecovery bool
+	FailureThreshold int
+	SuccessThreshold int
+	RepeatInterval   time.Duration
+	MaxRepeats       int
+	Flap             *FlapDetector
+	Store            StateStore
 
-	mu           sync.Mutex
-	lastStatus   map[string]check.Status
-	lastNotified map[string]time.Time
+	mu     sync.Mutex
+	states map[string]*State
+}
+
+// State is the per-check memory of a SimplePolicy. Status is the last status
+// that was notified (or silently accepted), so a transition held back by the
+// cooldown is retried on the next result.
+type State struct {
+	Status       check.Status   `json:"status"`
+	LastNotified time.Time      `json:"last_notified"`
+	Failures     int            `json:"failures"`
+	Successes    int            `json:"successes"`
+	History      []check.Status `json:"history,omitempty"`
+	Flapping     bool           `json:"flapping,omitempty"`
+	FailingSince time.Time      `json:"failing_since"`
+	Repeats      int            `json:"repeats,omitempty"`
+	IncidentID   string         `json:"incident_id,omitempty"`
 }
 
 func NewSimplePolicy(cooldown time.Duration, notifyOnRecovery bool) *SimplePolicy {
 	return &SimplePolicy{
 		Cooldown:         cooldown,
 		NotifyOnRecovery: notifyOnRecovery,
-		lastStatus:       make(map[string]check.Status),
-		lastNotified:     make(map[string]time.Time),
+		states:           make(map[string]*State),
 	}
 }
 
-func (p *SimplePolicy) Evaluate(ctx context.Context, res check.Result) (*notify.Event, error) {
+func (p *SimplePolicy) Evaluate(ctx context.Context, res check.Result) (event *notify.Event, saveErr error) {
 	_ = ctx
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	p.lastStatus[res.Name] = res.Status
+	st := p.state(res.Name)
+	before := st.clone()
+	defer func() {
+		if p.Store != nil && !reflect.DeepEqual(before, *st) {
+			if err := p.Store.Save(res.Name, *st); err != nil {
+				saveErr = fmt.Errorf("save state %q: %w", res.Name, err)
+			}
+		}
+	}()
+
+	if res.Status == check.StatusOK {
+		if st.Successes < threshold(p.SuccessThreshold) {
+			st.Successes++
+		}
+		st.Failures = 0
+	} else {
+		if st.Failures < threshold(p.FailureThreshold) {
+			st.Failures++
+		}
+		st.Successes = 0
+	}
 
 	now := time.Now()
-	if p.Cooldown > 0 {
-		if lastNotifyAt, ok := p.lastNotified[res.Name]; ok {
-			if now.Sub(lastNotifyAt) < p.Cooldown {
-				return nil, nil
-			}
+	prev := st.Status
+	if p.Flap != nil {
+		change, rate := p.Flap.observe(st, res.Status)
+		switch change {
+		case flapStarted:
+			st.LastNotified = now
+			summary := i18n.M("policy.flap_started", res.Name, rate)
+			return withIncident(newEvent(res, prev, summary, now, "flapping", "started"), st), nil
+		case flapStopped:
+			st.LastNotified = now
+			p.accept(st, res.Status, now)
+			summary := i18n.M("policy.flap_stopped", res.Name, res.Status)
+			return withIncident(newEvent(res, prev, summary, now, "flapping", "stopped"), st), nil
+		}
+		if st.Flapping {
+			return nil, nil
+		}
+	}
+
+	if res.Status == prev {
+		return p.remind(st, res, now), nil
+	}
+
+	recovered := res.Status == check.StatusOK
+	if prev == check.StatusOK && st.Failures < threshold(p.FailureThreshold) {
+		return nil, nil
+	}
+	if recovered && st.Successes < threshold(p.SuccessThreshold) {
+		return nil, nil
+	}
+	if recovered && !p.NotifyOnRecovery {
+		p.accept(st, res.Status, now)
+		return nil, nil
+	}
+
+	if p.Cooldown > 0 && !st.LastNotified.IsZero() {
+		if now.Sub(st.LastNotified) < p.Cooldown {
+			return nil, nil
+		}
+	}
+
+	failingSince, incidentID := st.FailingSince, st.IncidentID
+	p.accept(st, res.Status, now)
+	st.LastNotified = now
+	summary := i18n.M("policy.changed", res.Name, prev, res.Status)
+	if recovered {
+		summary = i18n.M("policy.recovered", res.Name, prev, res.Status)
+		if !failingSince.IsZero() {
+			summary = i18n.M("policy.recovered_after", res.Name, prev, res.Status, FormatElapsed(now.Sub(failingSince)))
 		}
+	} else if res.Status == check.StatusUnknown {
+		summary = i18n.M("policy.scan_failed", res.Name)
+	}
+	ev := withIncident(newEvent(res, prev, summary, now), st)
+	if recovered {
+		ev.IncidentID = incidentID
+		ev.StartedAt = failingSince
+		ev.ResolvedAt = now
+	}
+	return ev, nil
+}
+
+// accept records status as the check's alert state. Entering a failure opens
+// an incident and recovering resolves it.
+func (p *SimplePolicy) accept(st *State, status check.Status, now time.Time) {
+	switch {
+	case status == check.StatusOK:
+		st.FailingSince = time.Time{}
+		st.IncidentID = ""
+	case st.Status == check.StatusOK || st.IncidentID == "":
+		st.FailingSince = now
+		st.IncidentID = newIncidentID(now)
+	}
+	if status != st.Status {
+		st.Repeats = 0
+	}
+	st.Status = status
+}
+
+// remind re-announces a check that stays in a failure state every
+// RepeatInterval, at most MaxRepeats times (0 means no limit).
+func (p *SimplePolicy) remind(st *State, res check.Result, now time.Time) *notify.Event {
+	if p.RepeatInterval <= 0 || st.Status == check.StatusOK {
+		return nil
+	}
+	if p.MaxRepeats > 0 && st.Repeats >= p.MaxRepeats {
+		return nil
+	}
+	if now.Sub(st.LastNotified) < p.RepeatInterval {
+		return nil
+	}
+
+	st.Repeats++
+	st.LastNotified = now
+	summary := i18n.M("policy.reminder", res.Name, res.Status, FormatElapsed(now.Sub(st.FailingSince)))
+	return withIncident(newEvent(res, st.Status, summary, now, "reminder", strconv.Itoa(st.Repeats)), st)
+}
+
+func newEvent(res check.Result, prev check.Status, summary i18n.Message, now time.Time, extra ...string) *notify.Event {
+	labels := make(map[string]string, len(res.Labels)+2)
+	for k, v := range res.Labels {
+		labels[k] = v
+	}
+	labels["status"] = string(res.Status)
+	labels["previous_status"] = string(prev)
+	for i := 0; i+1 < len(extra); i += 2 {
+		labels[extra[i]] = extra[i+1]
+	}
+	event := &notify.Event{
+		Service:        res.Name,
+		Status:         string(res.Status),
+		PreviousStatus: string(prev),
+		Labels:         labels,
+		OccurredAt:     now,
+	}
+	event.SetSummary(summary)
+	event.SetDetails(res.Message)
+	event.Items = res.Details
+	return event
+}
+
+func withIncident(event *notify.Event, st *State) *notify.Event {
+	event.DedupKey = DedupKey(event.Service)
+	event.IncidentID = st.IncidentID
+	event.StartedAt = st.FailingSince
+	return event
+}
+
+// DedupKey is the stable key downstream tools use to group alerts of a check.
+func DedupKey(name string) string {
+	return "healthd/" + name
+}
+
+func newIncidentID(now time.Time) string {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return now.Format("20060102T150405.000000000")
+	}
+	return now.Format("20060102T150405") + "-" + hex.EncodeToString(buf)
+}
+
+// FormatElapsed renders a duration like "3h12m" or "2d4h0m".
+func FormatElapsed(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	minutes := int(d.Minutes())
+	days := minutes / (24 * 60)
+	hours := minutes / 60 % 24
+	minutes %= 60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd%dh%dm", days, hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+func (p *SimplePolicy) Status(name string) (check.Status, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	st, ok := p.states[name]
+	if !ok {
+		return "", false
 	}
+	return st.Status, true
+}
 
-	p.lastNotified[res.Name] = now
-	summary := fmt.Sprintf("%s 狀態：%s", res.Name, res.Status)
-	if res.Status == check.StatusUnknown {
-		summary = fmt.Sprintf("%s 掃描失敗", res.Name)
+// Restore loads previously saved check states from Store.
+func (p *SimplePolicy) Restore() error {
+	if p.Store == nil {
+		return nil
+	}
+	states, err := p.Store.Load()
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for name, st := range states {
+		st := st
+		p.states[name] = &st
 	}
-	return &notify.Event{
-		Service:    res.Name,
-		Status:     string(res.Status),
-		Summary:    summary,
-		Details:    res.Message,
-		Labels:     map[string]string{"status": string(res.Status)},
-		OccurredAt: now,
-	}, nil
+	return nil
+}
+
+func (p *SimplePolicy) state(name string) *State {
+	st, ok := p.states[name]
+	if !ok {
+		st = &State{Status: check.StatusOK}
+		p.states[name] = st
+	}
+	return st
+}
+
+func threshold(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+func (s State) clone() State {
+	s.History = append([]check.Status(nil), s.History...)
+	return s
 }
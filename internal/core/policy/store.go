@@ -0,0 +1,90 @@
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// StateStore persists per-check policy state so restarts keep cooldowns and
+// alert states.
+type StateStore interface {
+	Load() (map[string]State, error)
+	Save(name string, st State) error
+}
+
+// FileStore keeps all states in one JSON file, rewritten atomically on each save.
+type FileStore struct {
+	Path string
+
+	mu     sync.Mutex
+	states map[string]State
+}
+
+func NewFileStore(path string) *FileStore {
+	return &FileStore{Path: path}
+}
+
+func (s *FileStore) Load() (map[string]State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.loadLocked(); err != nil {
+		return nil, err
+	}
+	out := make(map[string]State, len(s.states))
+	for name, st := range s.states {
+		out[name] = st.clone()
+	}
+	return out, nil
+}
+
+func (s *FileStore) Save(name string, st State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.loadLocked(); err != nil {
+		return err
+	}
+	s.states[name] = st.clone()
+
+	data, err := json.MarshalIndent(s.states, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(s.Path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
+
+func (s *FileStore) loadLocked() error {
+	if s.states != nil {
+		return nil
+	}
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		s.states = make(map[string]State)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	states := make(map[string]State)
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &states); err != nil {
+			return err
+		}
+	}
+	s.states = states
+	return nil
+}
@@ -0,0 +1,121 @@
+package route
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/notify"
+)
+
+// Matcher selects events for a route. Empty fields match everything.
+type Matcher struct {
+	// Name is an exact check name, or a glob when it contains *, ? or [.
+	Name      string
+	NameRegex *regexp.Regexp
+	Type      string
+	Status    string
+	// MinStatus matches events at least this severe, see check.Severity,
+	// and recoveries from such a status.
+	MinStatus string
+	Labels    map[string]string
+}
+
+// NewMatcher compiles nameRegex, which must match the whole check name.
+func NewMatcher(name, nameRegex, typ, status, minStatus string, labels map[string]string) (Matcher, error) {
+	m := Matcher{Name: name, Type: typ, Status: status, MinStatus: minStatus, Labels: labels}
+	if isGlob(name) {
+		if _, err := path.Match(name, ""); err != nil {
+			return Matcher{}, fmt.Errorf("name %q: %w", name, err)
+		}
+	}
+	if nameRegex != "" {
+		re, err := regexp.Compile("^(?:" + nameRegex + ")$")
+		if err != nil {
+			return Matcher{}, fmt.Errorf("name_regex: %w", err)
+		}
+		m.NameRegex = re
+	}
+	return m, nil
+}
+
+func (m Matcher) Matches(event notify.Event) bool {
+	if m.Name != "" {
+		if isGlob(m.Name) {
+			if ok, _ := path.Match(m.Name, event.Service); !ok {
+				return false
+			}
+		} else if m.Name != event.Service {
+			return false
+		}
+	}
+	if m.NameRegex != nil && !m.NameRegex.MatchString(event.Service) {
+		return false
+	}
+	if m.Type != "" && m.Type != event.Type {
+		return false
+	}
+	if m.Status != "" && !strings.EqualFold(m.Status, event.Status) {
+		return false
+	}
+	if m.MinStatus != "" && !m.severeEnough(event) {
+		return false
+	}
+	for k, v := range m.Labels {
+		if event.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Route sends matching events to To. Routes are tried in order; a match on a
+// route with Continue false stops the search. Default routes only apply when
+// no other route matched.
+type Route struct {
+	Match    Matcher
+	To       []string
+	Continue bool
+	Default  bool
+}
+
+// Select returns the indexes of the routes event goes to, in order.
+func Select(routes []Route, event notify.Event) []int {
+	var out []int
+	for i, r := range routes {
+		if r.Default || !r.Match.Matches(event) {
+			continue
+		}
+		out = append(out, i)
+		if !r.Continue {
+			return out
+		}
+	}
+	if len(out) > 0 {
+		return out
+	}
+	for i, r := range routes {
+		if r.Default && r.Match.Matches(event) {
+			return append(out, i)
+		}
+	}
+	return nil
+}
+
+// severeEnough reports whether event meets MinStatus. A recovery counts with
+// its previous status, so a route that got the failure also gets the
+// resolve.
+func (m Matcher) severeEnough(event notify.Event) bool {
+	threshold := check.Severity(check.Status(m.MinStatus))
+	if check.Severity(check.Status(event.Status)) >= threshold {
+		return true
+	}
+	return strings.EqualFold(event.Status, string(check.StatusOK)) && event.PreviousStatus != "" &&
+		check.Severity(check.Status(event.PreviousStatus)) >= threshold
+}
+
+func isGlob(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
@@ -0,0 +1,244 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const defaultWorkers = 16
+
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// Job is a unit of work owned by a Pool. It runs once at start, then on its
+// cron Schedule or Interval. Run returning false removes it from the schedule.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Schedule string
+	RunOnce  bool
+	Run      func(ctx context.Context) bool
+}
+
+// Pool runs all interval and cron jobs on a single cron instance and caps the
+// number of jobs executing at the same time.
+type Pool struct {
+	Workers int
+
+	mu      sync.Mutex
+	jobs    []Job
+	cron    *cron.Cron
+	ctx     context.Context
+	sem     chan struct{}
+	running sync.WaitGroup
+	active  int
+	started bool
+	stopped bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func NewPool(workers int) *Pool {
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	return &Pool{
+		Workers: workers,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Add registers a job. It must be called before Start.
+func (p *Pool) Add(job Job) error {
+	if job.Run == nil {
+		return fmt.Errorf("job %q has no run func", job.Name)
+	}
+	if job.Schedule != "" {
+		if _, err := cronParser.Parse(job.Schedule); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.started {
+		return fmt.Errorf("scheduler already started")
+	}
+	p.jobs = append(p.jobs, job)
+	return nil
+}
+
+func (p *Pool) Start(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.started {
+		return fmt.Errorf("scheduler already started")
+	}
+	p.started = true
+	p.ctx = ctx
+	p.sem = make(chan struct{}, p.Workers)
+	p.cron = cron.New(cron.WithParser(cronParser))
+
+	p.active = len(p.jobs)
+	if p.active == 0 {
+		close(p.done)
+	}
+	for _, job := range p.jobs {
+		go p.launch(job)
+	}
+	p.cron.Start()
+	return nil
+}
+
+// Stop prevents new runs and waits for in-flight jobs to finish or ctx to end.
+func (p *Pool) Stop(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.started || p.stopped {
+		p.mu.Unlock()
+		return nil
+	}
+	p.stopped = true
+	close(p.stop)
+	p.mu.Unlock()
+
+	p.cron.Stop()
+	drained := make(chan struct{})
+	go func() {
+		p.running.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Done is closed once every job has finished and none remain scheduled.
+func (p *Pool) Done() <-chan struct{} {
+	return p.done
+}
+
+func (p *Pool) launch(job Job) {
+	if !p.exec(job) || job.RunOnce {
+		p.retire()
+		return
+	}
+
+	var schedule cron.Schedule
+	switch {
+	case job.Schedule != "":
+		s, err := cronParser.Parse(job.Schedule)
+		if err != nil {
+			p.retire()
+			return
+		}
+		schedule = s
+	case job.Interval > 0:
+		schedule = every(job.Interval)
+	default:
+		p.retire()
+		return
+	}
+
+	e := &entry{pool: p, job: job}
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		p.retire()
+		return
+	}
+	e.id = p.cron.Schedule(schedule, e)
+	p.mu.Unlock()
+}
+
+// exec waits for a free worker slot and runs the job.
+func (p *Pool) exec(job Job) bool {
+	select {
+	case p.sem <- struct{}{}:
+	case <-p.stop:
+		return false
+	case <-p.ctx.Done():
+		return false
+	}
+
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		<-p.sem
+		return false
+	}
+	p.running.Add(1)
+	p.mu.Unlock()
+
+	defer func() {
+		<-p.sem
+		p.running.Done()
+	}()
+	return job.Run(p.ctx)
+}
+
+func (p *Pool) retire() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active--
+	if p.active == 0 {
+		close(p.done)
+	}
+}
+
+// entry is a scheduled job; overlapping runs of the same job are skipped.
+type entry struct {
+	pool *Pool
+	job  Job
+	id   cron.EntryID
+
+	mu      sync.Mutex
+	busy    bool
+	retired bool
+}
+
+func (e *entry) Run() {
+	e.mu.Lock()
+	if e.busy || e.retired {
+		e.mu.Unlock()
+		return
+	}
+	e.busy = true
+	e.mu.Unlock()
+
+	keep := e.pool.exec(e.job)
+
+	e.mu.Lock()
+	e.busy = false
+	if keep || e.retired {
+		e.mu.Unlock()
+		return
+	}
+	e.retired = true
+	e.mu.Unlock()
+
+	select {
+	case <-e.pool.stop:
+	case <-e.pool.ctx.Done():
+	default:
+		e.pool.mu.Lock()
+		id := e.id
+		e.pool.mu.Unlock()
+		e.pool.cron.Remove(id)
+	}
+	e.pool.retire()
+}
+
+// every is a fixed-delay schedule that, unlike cron.Every, keeps sub-second precision.
+type every time.Duration
+
+func (d every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(d))
+}
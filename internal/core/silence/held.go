@@ -0,0 +1,71 @@
+package silence
+
+import (
+	"sync"
+
+	"services-health-check/internal/core/notify"
+)
+
+// Held keeps the latest silenced event of every check so that a state change
+// made during a silence is announced once the silence is over. Checks are
+// keyed by event.Service.
+type Held struct {
+	mu        sync.Mutex
+	events    map[string]notify.Event
+	announced map[string]string
+}
+
+func NewHeld() *Held {
+	return &Held{events: make(map[string]notify.Event), announced: make(map[string]string)}
+}
+
+// Add holds a silenced event, replacing the previous one of its check.
+func (h *Held) Add(event notify.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events[event.Service] = event
+}
+
+// Sent records an event that is about to be sent and reports whether it
+// should be dropped instead: a recovery from a failure that began during a
+// silence and was never announced.
+func (h *Held) Sent(event notify.Event) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, held := h.events[event.Service]
+	delete(h.events, event.Service)
+	if held && event.Status == "OK" && h.lastAnnounced(event.Service) == "OK" {
+		return true
+	}
+	h.announced[event.Service] = event.Status
+	return false
+}
+
+// Expired releases the held events that silenced no longer covers. It
+// returns those whose status differs from the last one announced for their
+// check, such as a CRIT that started during the silence and is still open.
+func (h *Held) Expired(silenced func(notify.Event) bool) []notify.Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var out []notify.Event
+	for service, event := range h.events {
+		if silenced(event) {
+			continue
+		}
+		delete(h.events, service)
+		if event.Status == h.lastAnnounced(service) {
+			continue
+		}
+		h.announced[service] = event.Status
+		out = append(out, event)
+	}
+	return out
+}
+
+// lastAnnounced treats a check never announced as OK.
+func (h *Held) lastAnnounced(service string) string {
+	if status, ok := h.announced[service]; ok {
+		return status
+	}
+	return "OK"
+}
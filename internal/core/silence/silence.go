@@ -0,0 +1,105 @@
+package silence
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"services-health-check/internal/core/notify"
+)
+
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// Matcher selects events by exact check name, type, status and labels.
+// Empty fields match everything.
+type Matcher struct {
+	Name   string
+	Type   string
+	Status string
+	Labels map[string]string
+}
+
+func (m Matcher) Matches(event notify.Event) bool {
+	if m.Name != "" && m.Name != event.Service {
+		return false
+	}
+	if m.Type != "" && m.Type != event.Type {
+		return false
+	}
+	if m.Status != "" && !strings.EqualFold(m.Status, event.Status) {
+		return false
+	}
+	for k, v := range m.Labels {
+		if event.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Silence suppresses matching events either once (Start..End) or on a
+// recurring window that opens on each cron Schedule tick and lasts Duration.
+type Silence struct {
+	Name     string
+	Match    Matcher
+	Start    time.Time
+	End      time.Time
+	Schedule cron.Schedule
+	Duration time.Duration
+}
+
+// New builds a silence. start/end are RFC3339 and either may be empty for an
+// open-ended window; schedule and duration define a recurring window instead.
+func New(name string, match Matcher, start, end, schedule string, duration time.Duration) (Silence, error) {
+	s := Silence{Name: name, Match: match, Duration: duration}
+	if schedule != "" {
+		sched, err := cronParser.Parse(schedule)
+		if err != nil {
+			return Silence{}, fmt.Errorf("silence %q schedule: %w", name, err)
+		}
+		if duration <= 0 {
+			return Silence{}, fmt.Errorf("silence %q: duration is required with schedule", name)
+		}
+		s.Schedule = sched
+		return s, nil
+	}
+	if start == "" && end == "" {
+		return Silence{}, fmt.Errorf("silence %q: start/end or schedule is required", name)
+	}
+	if start != "" {
+		t, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return Silence{}, fmt.Errorf("silence %q start: %w", name, err)
+		}
+		s.Start = t
+	}
+	if end != "" {
+		t, err := time.Parse(time.RFC3339, end)
+		if err != nil {
+			return Silence{}, fmt.Errorf("silence %q end: %w", name, err)
+		}
+		s.End = t
+	}
+	return s, nil
+}
+
+func (s Silence) Active(now time.Time) bool {
+	if s.Schedule != nil {
+		// The window is open if a tick happened within the last Duration.
+		next := s.Schedule.Next(now.Add(-s.Duration))
+		return !next.After(now)
+	}
+	if !s.Start.IsZero() && now.Before(s.Start) {
+		return false
+	}
+	if !s.End.IsZero() && !now.Before(s.End) {
+		return false
+	}
+	return true
+}
+
+func (s Silence) Silences(event notify.Event, now time.Time) bool {
+	return s.Active(now) && s.Match.Matches(event)
+}
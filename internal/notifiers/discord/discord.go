@@ -5,11 +5,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/i18n"
 	"services-health-check/internal/core/notify"
 	"services-health-check/internal/notifiers/format"
 )
@@ -18,6 +20,7 @@ type Notifier struct {
 	NameValue string
 	URL       string
 	Username  string
+	Templates format.Templates
 	Timeout   time.Duration
 }
 
@@ -46,16 +49,22 @@ func (n *Notifier) Name() string {
 }
 
 func (n *Notifier) Send(ctx context.Context, event notify.Event) error {
+	title, err := n.Templates.TitleOr(event, fmt.Sprintf("[%s] %s", event.Status, event.Service))
+	if err != nil {
+		return err
+	}
+	description, err := n.Templates.BodyOr(event, event.Summary)
+	if err != nil {
+		return err
+	}
 	body, err := json.Marshal(payload{
 		Embeds: []embed{
 			{
-				Title:       fmt.Sprintf("[%s] %s", event.Status, event.Service),
-				Description: event.Summary,
+				Title:       title,
+				Description: description,
 				Color:       statusColor(event.Status),
-				Fields: []embedField{
-					{Name: "Details", Value: formatDetails(event.Details), Inline: false},
-				},
-				Timestamp: event.OccurredAt.Format(time.RFC3339),
+				Fields:      embedFields(event, n.Templates.Body == nil),
+				Timestamp:   event.OccurredAt.Format(time.RFC3339),
 			},
 		},
 		Username: n.Username,
@@ -77,11 +86,97 @@ func (n *Notifier) Send(ctx context.Context, event notify.Event) error {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("discord status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	return notify.CheckResponse("discord", resp)
+}
+
+// embedFields lists the details and incident metadata. A body template owns
+// the details, so they are left out when one is set. Structured items get one
+// field each, inline unless they have children.
+func embedFields(event notify.Event, withDetails bool) []embedField {
+	meta := metaFields(event)
+	var fields []embedField
+	if withDetails && len(event.Items) > 0 {
+		if msg := strings.TrimSpace(event.Details); msg != "" {
+			fields = append(fields, embedField{Name: i18n.T(event.Locale, "label.details"), Value: truncate(msg, maxFieldValue, event.Locale)})
+		}
+		fields = append(fields, itemFields(event.Items, maxFields-len(fields)-len(meta), event.Locale)...)
+	} else if withDetails {
+		fields = append(fields, embedField{Name: i18n.T(event.Locale, "label.details"), Value: formatDetails(event.Details, event.Locale), Inline: false})
+	}
+	return append(fields, meta...)
+}
+
+const (
+	maxFields     = 25
+	maxFieldName  = 256
+	maxFieldValue = 1024
+	// blank stands in for an empty field name or value, which Discord rejects.
+	blank = "\u200b"
+)
+
+func itemFields(items []check.Detail, limit int, locale string) []embedField {
+	var fields []embedField
+	for i, d := range items {
+		if len(fields) == limit-1 && i < len(items)-1 {
+			fields = append(fields, embedField{Name: blank, Value: fmt.Sprintf("... %s", i18n.T(locale, "label.truncated"))})
+			break
+		}
+		name := format.ItemLabel(d)
+		if name == "" {
+			name = blank
+		}
+		lines := []string{}
+		if value := format.ItemValue(d, format.Code); value != "" {
+			lines = append(lines, value)
+		}
+		appendChildren(&lines, d.Children, 0)
+		value := strings.Join(lines, "\n")
+		if value == "" {
+			value = blank
+		}
+		fields = append(fields, embedField{
+			Name:   truncate(name, maxFieldName, locale),
+			Value:  truncate(value, maxFieldValue, locale),
+			Inline: len(d.Children) == 0,
+		})
+	}
+	return fields
+}
+
+func appendChildren(lines *[]string, items []check.Detail, depth int) {
+	for _, d := range items {
+		*lines = append(*lines, strings.Repeat("  ", depth)+"- "+format.ItemText(d, format.Code))
+		appendChildren(lines, d.Children, depth+1)
+	}
+}
+
+func truncate(s string, limit int, locale string) string {
+	if len(s) <= limit {
+		return s
+	}
+	suffix := "..." + i18n.T(locale, "label.truncated")
+	cut := limit - len(suffix)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + suffix
+}
+
+func metaFields(event notify.Event) []embedField {
+	var fields []embedField
+	if event.IncidentID != "" {
+		fields = append(fields, embedField{Name: i18n.T(event.Locale, "label.incident"), Value: "`" + event.IncidentID + "`", Inline: true})
+	}
+	if !event.StartedAt.IsZero() {
+		fields = append(fields, embedField{Name: i18n.T(event.Locale, "label.started"), Value: event.StartedAt.Format(time.RFC3339), Inline: true})
+	}
+	if !event.ResolvedAt.IsZero() {
+		fields = append(fields, embedField{Name: i18n.T(event.Locale, "label.resolved"), Value: event.ResolvedAt.Format(time.RFC3339), Inline: true})
+	}
+	if event.DedupKey != "" {
+		fields = append(fields, embedField{Name: i18n.T(event.Locale, "label.dedup"), Value: "`" + event.DedupKey + "`", Inline: true})
 	}
-	return nil
+	return fields
 }
 
 func statusColor(status string) int {
@@ -97,10 +192,10 @@ func statusColor(status string) int {
 	}
 }
 
-func formatDetails(details string) string {
+func formatDetails(details, locale string) string {
 	normalized := format.DetailsList(details)
 	if len(normalized) > 900 {
-		normalized = normalized[:900] + "\n- ...（已截斷）"
+		normalized = normalized[:900] + "\n- ..." + i18n.T(locale, "label.truncated")
 	}
 	return "```\n" + normalized + "\n```"
 }
@@ -71,13 +71,16 @@ func buildDetailsList(details, bullet, subBullet string) string {
 	return strings.Join(out, "\n")
 }
 
+// examplePrefixes introduce the sample items of a check message, e.g. the
+// unready deployments listed by the k8s checker, in each supported locale.
+var examplePrefixes = []string{"例:", "例：", "e.g.:", "e.g."}
+
 func trimExamplePrefix(input string) (string, bool) {
 	trimmed := strings.TrimSpace(input)
-	if strings.HasPrefix(trimmed, "例:") {
-		return strings.TrimSpace(strings.TrimPrefix(trimmed, "例:")), true
-	}
-	if strings.HasPrefix(trimmed, "例：") {
-		return strings.TrimSpace(strings.TrimPrefix(trimmed, "例：")), true
+	for _, prefix := range examplePrefixes {
+		if strings.HasPrefix(trimmed, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(trimmed, prefix)), true
+		}
 	}
 	return "", false
 }
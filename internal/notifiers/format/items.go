@@ -0,0 +1,84 @@
+package format
+
+import (
+	"strings"
+
+	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/notify"
+)
+
+// EventDetails renders the details of an event as a plain-text list. Events
+// with structured items list the check message followed by the items; older
+// string-only details still go through DetailsList.
+func EventDetails(event notify.Event) string {
+	if len(event.Items) == 0 {
+		return DetailsList(event.Details)
+	}
+	var lines []string
+	if msg := strings.TrimSpace(event.Details); msg != "" {
+		lines = append(lines, "- "+msg)
+	}
+	lines = append(lines, ItemsList(event.Items))
+	return strings.Join(lines, "\n")
+}
+
+// ItemsList renders items as "- label: value" lines with children indented
+// below their parent. Domain and pod values are wrapped in backticks.
+func ItemsList(items []check.Detail) string {
+	var lines []string
+	appendItems(&lines, items, 0)
+	if len(lines) == 0 {
+		return "n/a"
+	}
+	return strings.Join(lines, "\n")
+}
+
+func appendItems(lines *[]string, items []check.Detail, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, d := range items {
+		*lines = append(*lines, indent+"- "+ItemText(d, Code))
+		appendItems(lines, d.Children, depth+1)
+	}
+}
+
+// ItemText joins the label and the value of d, passing domain and pod values
+// through code.
+func ItemText(d check.Detail, code func(string) string) string {
+	label := ItemLabel(d)
+	value := ItemValue(d, code)
+	switch {
+	case label == "":
+		return value
+	case value == "":
+		return label
+	default:
+		return label + ": " + value
+	}
+}
+
+// ItemLabel is the label of d in the default locale, or in the event locale
+// once the event has been localized.
+func ItemLabel(d check.Detail) string {
+	if d.Label.IsZero() {
+		return ""
+	}
+	return d.Label.String()
+}
+
+// ItemValue formats the value of d according to its kind.
+func ItemValue(d check.Detail, code func(string) string) string {
+	if d.Value == "" {
+		return ""
+	}
+	switch d.Kind {
+	case check.KindDomain, check.KindPod:
+		return code(d.Value)
+	default:
+		return d.Value
+	}
+}
+
+// Code wraps s in backticks for markdown-like chat formats.
+func Code(s string) string {
+	return "`" + s + "`"
+}
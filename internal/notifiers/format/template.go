@@ -0,0 +1,104 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"services-health-check/internal/core/notify"
+)
+
+// Templates are optional per-channel overrides for the message title and
+// body. A nil template keeps the notifier's built-in wording.
+type Templates struct {
+	Title *template.Template
+	Body  *template.Template
+}
+
+// ParseTemplates parses the title and body templates of a channel; empty
+// strings leave the corresponding template nil.
+func ParseTemplates(name, title, body string) (Templates, error) {
+	var t Templates
+	if strings.TrimSpace(title) != "" {
+		parsed, err := ParseTemplate(name+".title", title)
+		if err != nil {
+			return Templates{}, fmt.Errorf("title_template: %w", err)
+		}
+		t.Title = parsed
+	}
+	if strings.TrimSpace(body) != "" {
+		parsed, err := ParseTemplate(name+".body", body)
+		if err != nil {
+			return Templates{}, fmt.Errorf("body_template: %w", err)
+		}
+		t.Body = parsed
+	}
+	return t, nil
+}
+
+// TitleOr renders the title template, or returns fallback when none is set.
+func (t Templates) TitleOr(event notify.Event, fallback string) (string, error) {
+	return render(t.Title, event, fallback)
+}
+
+// BodyOr renders the body template, or returns fallback when none is set.
+func (t Templates) BodyOr(event notify.Event, fallback string) (string, error) {
+	return render(t.Body, event, fallback)
+}
+
+func render(tmpl *template.Template, event notify.Event, fallback string) (string, error) {
+	if tmpl == nil {
+		return fallback, nil
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, event); err != nil {
+		return "", fmt.Errorf("render %s: %w", tmpl.Name(), err)
+	}
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
+
+// ParseTemplate parses a notifier template with the helpers shared by all
+// channels. Templates are executed against a notify.Event.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=zero").Funcs(TemplateFuncs()).Parse(text)
+}
+
+// TemplateFuncs returns the helper functions available in notifier templates.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"details": DetailsList,
+		"items":   ItemsList,
+		"json":    toJSON,
+		"upper":   strings.ToUpper,
+		"lower":   strings.ToLower,
+		"time":    formatTime,
+		"default": defaultValue,
+	}
+}
+
+// toJSON renders v as a JSON value so strings can be embedded in JSON bodies
+// without breaking quoting.
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func formatTime(layout string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
+
+func defaultValue(fallback, value string) string {
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+	return value
+}
@@ -15,6 +15,7 @@ import (
 type Notifier struct {
 	NameValue string
 	URL       string
+	Templates format.Templates
 	Timeout   time.Duration
 }
 
@@ -27,8 +28,15 @@ func (n *Notifier) Name() string {
 }
 
 func (n *Notifier) Send(ctx context.Context, event notify.Event) error {
-	text := fmt.Sprintf("[%s] %s\n%s", event.Status, event.Summary, format.DetailsList(event.Details))
-	body, err := json.Marshal(payload{Text: text})
+	title, err := n.Templates.TitleOr(event, fmt.Sprintf("[%s] %s", event.Status, event.Summary))
+	if err != nil {
+		return err
+	}
+	details, err := n.Templates.BodyOr(event, format.EventDetails(event))
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(payload{Text: title + "\n" + details})
 	if err != nil {
 		return err
 	}
@@ -46,8 +54,5 @@ func (n *Notifier) Send(ctx context.Context, event notify.Event) error {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("gchat status %d", resp.StatusCode)
-	}
-	return nil
+	return notify.CheckResponse("gchat", resp)
 }
@@ -0,0 +1,231 @@
+package line
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"services-health-check/internal/core/i18n"
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/notifiers/format"
+)
+
+const (
+	defaultURL = "https://api.line.me/v2/bot/message/push"
+
+	// LINE text messages are capped at 5000 characters.
+	maxTextRunes = 4500
+)
+
+// Notifier pushes messages to LINE users or groups through the Messaging API.
+type Notifier struct {
+	NameValue string
+	URL       string
+	Token     string
+	To        []string
+	Flex      bool
+	Templates format.Templates
+	Timeout   time.Duration
+}
+
+type payload struct {
+	To       string    `json:"to"`
+	Messages []message `json:"messages"`
+}
+
+type message struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	AltText  string    `json:"altText,omitempty"`
+	Contents *flexNode `json:"contents,omitempty"`
+}
+
+type flexNode struct {
+	Type            string      `json:"type"`
+	Layout          string      `json:"layout,omitempty"`
+	Text            string      `json:"text,omitempty"`
+	Weight          string      `json:"weight,omitempty"`
+	Size            string      `json:"size,omitempty"`
+	Color           string      `json:"color,omitempty"`
+	Wrap            bool        `json:"wrap,omitempty"`
+	Margin          string      `json:"margin,omitempty"`
+	Spacing         string      `json:"spacing,omitempty"`
+	BackgroundColor string      `json:"backgroundColor,omitempty"`
+	Contents        []*flexNode `json:"contents,omitempty"`
+	Header          *flexNode   `json:"header,omitempty"`
+	Body            *flexNode   `json:"body,omitempty"`
+	Footer          *flexNode   `json:"footer,omitempty"`
+}
+
+func (n *Notifier) Name() string {
+	return n.NameValue
+}
+
+func (n *Notifier) Send(ctx context.Context, event notify.Event) error {
+	return n.SendEach(ctx, event, func(ctx context.Context, send func(context.Context) error) error {
+		return send(ctx)
+	})
+}
+
+// SendEach pushes event to every recipient through do, so a retry layer can
+// retry a failed recipient without pushing again to the others. Recipients
+// set on ctx with notify.WithRecipients limit the push to those.
+func (n *Notifier) SendEach(ctx context.Context, event notify.Event, do func(context.Context, func(context.Context) error) error) error {
+	if n.Token == "" || len(n.To) == 0 {
+		return fmt.Errorf("line token and recipients are required")
+	}
+	title, err := n.Templates.TitleOr(event, fmt.Sprintf("[%s] %s", event.Status, event.Service))
+	if err != nil {
+		return err
+	}
+	var body string
+	if n.Templates.Body != nil {
+		if body, err = n.Templates.BodyOr(event, ""); err != nil {
+			return err
+		}
+	}
+	msg := textMessage(event, title, body)
+	if n.Flex {
+		msg = flexMessage(event, title, body)
+	}
+
+	url := n.URL
+	if url == "" {
+		url = defaultURL
+	}
+	client := &http.Client{Timeout: n.Timeout}
+
+	only := notify.Recipients(ctx)
+	var errs []error
+	for _, to := range n.To {
+		if only != nil && !slices.Contains(only, to) {
+			continue
+		}
+		p := payload{To: to, Messages: []message{msg}}
+		err := do(ctx, func(ctx context.Context) error {
+			return n.push(ctx, client, url, p)
+		})
+		if err != nil {
+			errs = append(errs, &notify.RecipientError{Recipient: to, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("line push failed: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func (n *Notifier) push(ctx context.Context, client *http.Client, url string, p payload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+n.Token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return notify.CheckResponse("line", resp)
+}
+
+// textMessage and flexMessage use body in place of the summary and details
+// when a body template rendered one.
+func textMessage(event notify.Event, title, body string) message {
+	if body == "" {
+		body = event.Summary + "\n" + plainDetails(event)
+	}
+	text := title + "\n" + body
+	if r := []rune(text); len(r) > maxTextRunes {
+		text = string(r[:maxTextRunes]) + "\n- ..." + i18n.T(event.Locale, "label.truncated")
+	}
+	return message{Type: "text", Text: text}
+}
+
+func flexMessage(event notify.Event, title, body string) message {
+	content := []*flexNode{
+		{Type: "text", Text: nonEmpty(event.Summary), Weight: "bold", Wrap: true},
+		{Type: "text", Text: truncateDetails(plainDetails(event), event.Locale), Size: "sm", Color: "#555555", Wrap: true},
+	}
+	if body != "" {
+		content = []*flexNode{{Type: "text", Text: truncateDetails(body, event.Locale), Size: "sm", Wrap: true}}
+	}
+	bubble := &flexNode{
+		Type: "bubble",
+		Header: &flexNode{
+			Type:            "box",
+			Layout:          "vertical",
+			BackgroundColor: statusColor(event.Status),
+			Contents: []*flexNode{
+				{Type: "text", Text: title, Weight: "bold", Size: "md", Color: "#FFFFFF", Wrap: true},
+			},
+		},
+		Body: &flexNode{
+			Type:     "box",
+			Layout:   "vertical",
+			Spacing:  "md",
+			Contents: content,
+		},
+		Footer: &flexNode{
+			Type:   "box",
+			Layout: "vertical",
+			Contents: []*flexNode{
+				{Type: "text", Text: event.OccurredAt.Format("2006-01-02 15:04:05 MST"), Size: "xs", Color: "#999999"},
+			},
+		},
+	}
+	return message{Type: "flex", AltText: truncateAlt(title + " " + event.Summary), Contents: bubble}
+}
+
+// plainDetails drops the backtick highlighting, which LINE renders literally.
+func plainDetails(event notify.Event) string {
+	return strings.ReplaceAll(format.EventDetails(event), "`", "")
+}
+
+func statusColor(status string) string {
+	switch strings.ToUpper(status) {
+	case "OK":
+		return "#2ECC71"
+	case "WARN":
+		return "#F1C40F"
+	case "CRIT":
+		return "#E74C3C"
+	default:
+		return "#95A5A6"
+	}
+}
+
+func truncateDetails(s, locale string) string {
+	if r := []rune(s); len(r) > 1500 {
+		return string(r[:1500]) + "\n- ..." + i18n.T(locale, "label.truncated")
+	}
+	return s
+}
+
+// Flex text components reject empty strings.
+func nonEmpty(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "-"
+	}
+	return s
+}
+
+func truncateAlt(s string) string {
+	if r := []rune(s); len(r) > 400 {
+		return string(r[:400])
+	}
+	return s
+}
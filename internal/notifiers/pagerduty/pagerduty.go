@@ -0,0 +1,137 @@
+package pagerduty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/core/policy"
+	"services-health-check/internal/notifiers/format"
+)
+
+const defaultURL = "https://events.pagerduty.com/v2/enqueue"
+
+// Notifier sends PagerDuty Events API v2 alerts: WARN/CRIT/UNKNOWN trigger an
+// alert and OK resolves it, both keyed by the check's dedup key. Flap events
+// are not sent: they share the key of the check's incident, and a flap that
+// stops on OK would resolve it.
+type Notifier struct {
+	NameValue  string
+	URL        string
+	RoutingKey string
+	Timeout    time.Duration
+}
+
+type payload struct {
+	RoutingKey  string        `json:"routing_key"`
+	EventAction string        `json:"event_action"`
+	DedupKey    string        `json:"dedup_key"`
+	Payload     *alertPayload `json:"payload,omitempty"`
+	Client      string        `json:"client,omitempty"`
+}
+
+type alertPayload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      string         `json:"severity"`
+	Timestamp     string         `json:"timestamp,omitempty"`
+	Component     string         `json:"component,omitempty"`
+	Class         string         `json:"class,omitempty"`
+	CustomDetails map[string]any `json:"custom_details,omitempty"`
+}
+
+func (n *Notifier) Name() string {
+	return n.NameValue
+}
+
+func (n *Notifier) Send(ctx context.Context, event notify.Event) error {
+	if strings.TrimSpace(n.RoutingKey) == "" {
+		return fmt.Errorf("pagerduty routing key is required")
+	}
+	if event.Labels["flapping"] != "" {
+		return nil
+	}
+
+	body, err := json.Marshal(buildPayload(n.RoutingKey, event))
+	if err != nil {
+		return err
+	}
+
+	url := n.URL
+	if strings.TrimSpace(url) == "" {
+		url = defaultURL
+	}
+	client := &http.Client{Timeout: n.Timeout}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return notify.CheckResponse("pagerduty", resp)
+}
+
+func buildPayload(routingKey string, event notify.Event) payload {
+	dedupKey := event.DedupKey
+	if dedupKey == "" {
+		dedupKey = policy.DedupKey(event.Service)
+	}
+
+	p := payload{
+		RoutingKey: routingKey,
+		DedupKey:   dedupKey,
+		Client:     "healthd",
+	}
+	if strings.EqualFold(event.Status, "OK") {
+		p.EventAction = "resolve"
+		return p
+	}
+
+	p.EventAction = "trigger"
+	p.Payload = &alertPayload{
+		Summary:   truncate(fmt.Sprintf("[%s] %s", event.Status, event.Summary), 1024),
+		Source:    event.Service,
+		Severity:  severity(event.Status),
+		Timestamp: event.OccurredAt.Format(time.RFC3339),
+		Component: event.Service,
+		Class:     event.Type,
+		CustomDetails: map[string]any{
+			"details":     format.EventDetails(event),
+			"incident_id": event.IncidentID,
+			"labels":      event.Labels,
+		},
+	}
+	return p
+}
+
+func severity(status string) string {
+	switch strings.ToUpper(status) {
+	case "CRIT":
+		return "critical"
+	case "WARN":
+		return "warning"
+	case "UNKNOWN":
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+func truncate(s string, limit int) string {
+	r := []rune(s)
+	if len(r) <= limit {
+		return s
+	}
+	return string(r[:limit-1]) + "…"
+}
@@ -5,11 +5,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
 
+	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/i18n"
 	"services-health-check/internal/core/notify"
 	"services-health-check/internal/notifiers/format"
 )
@@ -17,6 +18,7 @@ import (
 type Notifier struct {
 	NameValue string
 	URL       string
+	Templates format.Templates
 	Timeout   time.Duration
 }
 
@@ -48,27 +50,35 @@ func (n *Notifier) Name() string {
 }
 
 func (n *Notifier) Send(ctx context.Context, event notify.Event) error {
+	title, err := n.Templates.TitleOr(event, fmt.Sprintf("*[%s]* %s", event.Status, event.Service))
+	if err != nil {
+		return err
+	}
 	blocks := []block{
 		{
 			Type: "section",
-			Text: &blockText{Type: "mrkdwn", Text: fmt.Sprintf("*[%s]* %s", event.Status, event.Service)},
-		},
-		{
-			Type: "section",
-			Text: &blockText{Type: "mrkdwn", Text: event.Summary},
-		},
-		{
-			Type: "section",
-			Text: &blockText{Type: "mrkdwn", Text: formatDetails(event.Details)},
-		},
-		{
-			Type: "context",
-			Elements: []blockText{
-				{Type: "mrkdwn", Text: fmt.Sprintf("*狀態*: %s", event.Status)},
-				{Type: "mrkdwn", Text: fmt.Sprintf("*時間*: %s", event.OccurredAt.Format(time.RFC3339))},
-			},
+			Text: &blockText{Type: "mrkdwn", Text: title},
 		},
 	}
+	if n.Templates.Body != nil {
+		text, err := n.Templates.BodyOr(event, "")
+		if err != nil {
+			return err
+		}
+		blocks = append(blocks, block{Type: "section", Text: &blockText{Type: "mrkdwn", Text: text}})
+	} else if len(event.Items) > 0 {
+		blocks = append(blocks, block{Type: "section", Text: &blockText{Type: "mrkdwn", Text: event.Summary}})
+		if msg := strings.TrimSpace(event.Details); msg != "" {
+			blocks = append(blocks, block{Type: "section", Text: &blockText{Type: "mrkdwn", Text: msg}})
+		}
+		blocks = append(blocks, itemBlocks(event.Items)...)
+	} else {
+		blocks = append(blocks,
+			block{Type: "section", Text: &blockText{Type: "mrkdwn", Text: event.Summary}},
+			block{Type: "section", Text: &blockText{Type: "mrkdwn", Text: formatDetails(event.Details, event.Locale)}},
+		)
+	}
+	blocks = append(blocks, block{Type: "context", Elements: contextElements(event)})
 	body, err := json.Marshal(payload{
 		Text: fmt.Sprintf("[%s] %s", event.Status, event.Summary),
 		Attachments: []attachment{
@@ -96,19 +106,73 @@ func (n *Notifier) Send(ctx context.Context, event notify.Event) error {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("slack status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	return notify.CheckResponse("slack", resp)
+}
+
+func contextElements(event notify.Event) []blockText {
+	elements := []blockText{
+		{Type: "mrkdwn", Text: fmt.Sprintf("*%s*: %s", i18n.T(event.Locale, "label.status"), event.Status)},
+		{Type: "mrkdwn", Text: fmt.Sprintf("*%s*: %s", i18n.T(event.Locale, "label.time"), event.OccurredAt.Format(time.RFC3339))},
+	}
+	if event.IncidentID != "" {
+		elements = append(elements, blockText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*: `%s`", i18n.T(event.Locale, "label.incident"), event.IncidentID)})
+	}
+	if !event.StartedAt.IsZero() {
+		elements = append(elements, blockText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*: %s", i18n.T(event.Locale, "label.started"), event.StartedAt.Format(time.RFC3339))})
+	}
+	if !event.ResolvedAt.IsZero() {
+		elements = append(elements, blockText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*: %s", i18n.T(event.Locale, "label.resolved"), event.ResolvedAt.Format(time.RFC3339))})
+	}
+	if event.DedupKey != "" {
+		elements = append(elements, blockText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*: `%s`", i18n.T(event.Locale, "label.dedup"), event.DedupKey)})
+	}
+	return elements
+}
+
+// maxFields is the number of fields Slack accepts in one section block.
+const maxFields = 10
+
+// itemBlocks renders detail items as section fields, splitting them over as
+// many sections as needed.
+func itemBlocks(items []check.Detail) []block {
+	var blocks []block
+	for start := 0; start < len(items); start += maxFields {
+		end := min(start+maxFields, len(items))
+		fields := make([]blockText, 0, end-start)
+		for _, d := range items[start:end] {
+			fields = append(fields, blockText{Type: "mrkdwn", Text: itemField(d)})
+		}
+		blocks = append(blocks, block{Type: "section", Fields: fields})
+	}
+	return blocks
+}
+
+func itemField(d check.Detail) string {
+	var lines []string
+	if label := format.ItemLabel(d); label != "" {
+		lines = append(lines, "*"+label+"*")
+	}
+	if value := format.ItemValue(d, format.Code); value != "" {
+		lines = append(lines, value)
+	}
+	appendChildren(&lines, d.Children, 0)
+	return strings.Join(lines, "\n")
+}
+
+func appendChildren(lines *[]string, items []check.Detail, depth int) {
+	for _, d := range items {
+		*lines = append(*lines, strings.Repeat("    ", depth)+"• "+format.ItemText(d, format.Code))
+		appendChildren(lines, d.Children, depth+1)
 	}
-	return nil
 }
 
-func formatDetails(details string) string {
+func formatDetails(details, locale string) string {
+	label := i18n.T(locale, "label.details")
 	list := format.DetailsListForSlack(details)
 	if strings.TrimSpace(list) == "" || list == "n/a" {
-		return "*細節*: n/a"
+		return "*" + label + "*: n/a"
 	}
-	return "*細節*\n" + list
+	return "*" + label + "*\n" + list
 }
 
 func statusColor(status string) string {
@@ -4,12 +4,15 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"html"
 	"mime"
 	"net"
 	"net/smtp"
 	"strings"
 	"time"
 
+	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/i18n"
 	"services-health-check/internal/core/notify"
 	"services-health-check/internal/notifiers/format"
 )
@@ -26,6 +29,7 @@ type Notifier struct {
 	Timeout       time.Duration
 	ImplicitTLS   bool
 	SkipVerifyTLS bool
+	Templates     format.Templates
 }
 
 func (n *Notifier) Name() string {
@@ -50,21 +54,31 @@ func (n *Notifier) Send(ctx context.Context, event notify.Event) error {
 	if subject == "" {
 		subject = fmt.Sprintf("[%s] %s", event.Status, event.Summary)
 	}
+	subject, err := n.Templates.TitleOr(event, subject)
+	if err != nil {
+		return err
+	}
 	subject = mime.QEncoding.Encode("utf-8", subject)
 
-	details := format.DetailsList(event.Details)
+	details := format.EventDetails(event)
 	bodyLines := []string{
 		fmt.Sprintf("[%s] %s", event.Status, event.Summary),
-		fmt.Sprintf("服務: %s", event.Service),
-		fmt.Sprintf("狀態: %s", event.Status),
-		fmt.Sprintf("時間: %s", event.OccurredAt.Format(time.RFC3339)),
-		"",
-		"細節:",
-		details,
+		fmt.Sprintf("%s: %s", i18n.T(event.Locale, "label.service"), event.Service),
+		fmt.Sprintf("%s: %s", i18n.T(event.Locale, "label.status"), event.Status),
+		fmt.Sprintf("%s: %s", i18n.T(event.Locale, "label.time"), event.OccurredAt.Format(time.RFC3339)),
+	}
+	bodyLines = append(bodyLines, incidentLines(event)...)
+	bodyLines = append(bodyLines, "", i18n.T(event.Locale, "label.details")+":", details)
+	body, err := n.Templates.BodyOr(event, strings.Join(bodyLines, "\n"))
+	if err != nil {
+		return err
 	}
-	body := strings.Join(bodyLines, "\n")
 
-	msg := buildMessage(n.From, n.To, subject, body)
+	var htmlBody string
+	if n.Templates.Body == nil && len(event.Items) > 0 {
+		htmlBody = BuildHTML(event)
+	}
+	msg := buildMessage(n.From, n.To, subject, body, htmlBody, ThreadHeaders(event))
 	addr := fmt.Sprintf("%s:%d", n.Host, n.Port)
 
 	client, err := n.dialSMTP(ctx, addr)
@@ -136,13 +150,148 @@ func (n *Notifier) dialSMTP(ctx context.Context, addr string) (*smtp.Client, err
 	return client, nil
 }
 
-func buildMessage(from string, to []string, subject string, body string) string {
+func incidentLines(event notify.Event) []string {
+	if event.IncidentID == "" {
+		return nil
+	}
+	lines := []string{fmt.Sprintf("%s: %s", i18n.T(event.Locale, "label.incident"), event.IncidentID)}
+	if !event.StartedAt.IsZero() {
+		lines = append(lines, fmt.Sprintf("%s: %s", i18n.T(event.Locale, "label.started"), event.StartedAt.Format(time.RFC3339)))
+	}
+	if !event.ResolvedAt.IsZero() {
+		lines = append(lines, fmt.Sprintf("%s: %s", i18n.T(event.Locale, "label.resolved"), event.ResolvedAt.Format(time.RFC3339)))
+	}
+	if event.DedupKey != "" {
+		lines = append(lines, fmt.Sprintf("%s: %s", i18n.T(event.Locale, "label.dedup"), event.DedupKey))
+	}
+	return lines
+}
+
+// ThreadHeaders makes mail clients group every message of an incident into
+// one thread. The email that opens the incident carries the root Message-ID;
+// later ones get their own ID and refer back to the root.
+func ThreadHeaders(event notify.Event) []string {
+	if event.IncidentID == "" {
+		return nil
+	}
+	root := fmt.Sprintf("<%s@healthd>", event.IncidentID)
+	if opensIncident(event) {
+		return []string{
+			"Message-ID: " + root,
+			"X-Healthd-Incident: " + event.IncidentID,
+		}
+	}
+	return []string{
+		fmt.Sprintf("Message-ID: <%s.%d@healthd>", event.IncidentID, event.OccurredAt.UnixNano()),
+		"In-Reply-To: " + root,
+		"References: " + root,
+		"X-Healthd-Incident: " + event.IncidentID,
+	}
+}
+
+// opensIncident reports whether event is the first failure of its incident.
+// Escalations re-send that event and are replies like any other.
+func opensIncident(event notify.Event) bool {
+	if event.Status == string(check.StatusOK) || event.Labels["escalation"] != "" {
+		return false
+	}
+	return event.PreviousStatus == "" || event.PreviousStatus == string(check.StatusOK)
+}
+
+// boundary separates the plain and HTML parts. It cannot collide with the
+// escaped HTML body and is unlikely to appear in the plain one.
+const boundary = "healthd-alternative-7c1f0e"
+
+func buildMessage(from string, to []string, subject string, body string, htmlBody string, extra []string) string {
 	headers := []string{
 		"From: " + from,
 		"To: " + strings.Join(to, ", "),
 		"Subject: " + subject,
 		"MIME-Version: 1.0",
+	}
+	if htmlBody == "" {
+		headers = append(headers, "Content-Type: text/plain; charset=UTF-8")
+		headers = append(headers, extra...)
+		return strings.Join(headers, "\r\n") + "\r\n\r\n" + body
+	}
+	headers = append(headers, fmt.Sprintf("Content-Type: multipart/alternative; boundary=%q", boundary))
+	headers = append(headers, extra...)
+	parts := []string{
+		"--" + boundary,
 		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+		"--" + boundary,
+		"Content-Type: text/html; charset=UTF-8",
+		"",
+		htmlBody,
+		"--" + boundary + "--",
+		"",
+	}
+	return strings.Join(headers, "\r\n") + "\r\n\r\n" + strings.Join(parts, "\r\n")
+}
+
+// BuildHTML renders the event as an HTML page with the detail items in a
+// two-column table.
+func BuildHTML(event notify.Event) string {
+	esc := html.EscapeString
+	var b strings.Builder
+	b.WriteString("<html><body>\n")
+	fmt.Fprintf(&b, "<h3>[%s] %s</h3>\n", esc(event.Status), esc(event.Service))
+	fmt.Fprintf(&b, "<p>%s</p>\n", esc(event.Summary))
+	if msg := strings.TrimSpace(event.Details); msg != "" {
+		fmt.Fprintf(&b, "<p>%s</p>\n", esc(msg))
+	}
+	b.WriteString("<table border=\"1\" cellpadding=\"4\" cellspacing=\"0\">\n")
+	for _, d := range event.Items {
+		fmt.Fprintf(&b, "<tr><th align=\"left\">%s</th><td>%s%s</td></tr>\n",
+			esc(format.ItemLabel(d)), htmlValue(d), htmlList(d.Children))
+	}
+	rows := [][2]string{
+		{i18n.T(event.Locale, "label.status"), event.Status},
+		{i18n.T(event.Locale, "label.time"), event.OccurredAt.Format(time.RFC3339)},
+	}
+	if event.IncidentID != "" {
+		rows = append(rows, [2]string{i18n.T(event.Locale, "label.incident"), event.IncidentID})
+	}
+	for _, row := range rows {
+		fmt.Fprintf(&b, "<tr><th align=\"left\">%s</th><td>%s</td></tr>\n", esc(row[0]), esc(row[1]))
+	}
+	b.WriteString("</table>\n</body></html>")
+	return b.String()
+}
+
+func htmlList(items []check.Detail) string {
+	if len(items) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("<ul>")
+	for _, d := range items {
+		label := html.EscapeString(format.ItemLabel(d))
+		value := htmlValue(d)
+		switch {
+		case label == "":
+			b.WriteString("<li>" + value)
+		case value == "":
+			b.WriteString("<li>" + label)
+		default:
+			b.WriteString("<li>" + label + ": " + value)
+		}
+		b.WriteString(htmlList(d.Children) + "</li>")
+	}
+	b.WriteString("</ul>")
+	return b.String()
+}
+
+func htmlValue(d check.Detail) string {
+	value := html.EscapeString(d.Value)
+	switch d.Kind {
+	case check.KindDomain, check.KindPod:
+		return "<code>" + value + "</code>"
+	case check.KindURL:
+		return fmt.Sprintf("<a href=\"%s\">%s</a>", value, value)
+	default:
+		return value
 	}
-	return strings.Join(headers, "\r\n") + "\r\n\r\n" + body
 }
@@ -0,0 +1,191 @@
+package teams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"services-health-check/internal/core/i18n"
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/notifiers/format"
+)
+
+const cardContentType = "application/vnd.microsoft.card.adaptive"
+
+// Notifier posts an Adaptive Card to a Teams incoming webhook or Workflows URL.
+type Notifier struct {
+	NameValue string
+	URL       string
+	Templates format.Templates
+	Timeout   time.Duration
+}
+
+type payload struct {
+	Type        string       `json:"type"`
+	Attachments []attachment `json:"attachments"`
+}
+
+type attachment struct {
+	ContentType string `json:"contentType"`
+	Content     card   `json:"content"`
+}
+
+type card struct {
+	Schema  string         `json:"$schema"`
+	Type    string         `json:"type"`
+	Version string         `json:"version"`
+	Body    []element      `json:"body"`
+	MSTeams map[string]any `json:"msteams,omitempty"`
+}
+
+type element struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	Weight   string    `json:"weight,omitempty"`
+	Size     string    `json:"size,omitempty"`
+	Color    string    `json:"color,omitempty"`
+	Wrap     bool      `json:"wrap,omitempty"`
+	Spacing  string    `json:"spacing,omitempty"`
+	Style    string    `json:"style,omitempty"`
+	Bleed    bool      `json:"bleed,omitempty"`
+	Items    []element `json:"items,omitempty"`
+	Facts    []fact    `json:"facts,omitempty"`
+	IsSubtle bool      `json:"isSubtle,omitempty"`
+}
+
+type fact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+func (n *Notifier) Name() string {
+	return n.NameValue
+}
+
+func (n *Notifier) Send(ctx context.Context, event notify.Event) error {
+	content, err := n.buildCard(event)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(payload{
+		Type: "message",
+		Attachments: []attachment{
+			{ContentType: cardContentType, Content: content},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: n.Timeout}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return notify.CheckResponse("teams", resp)
+}
+
+func (n *Notifier) buildCard(event notify.Event) (card, error) {
+	title, err := n.Templates.TitleOr(event, fmt.Sprintf("[%s] %s", event.Status, event.Service))
+	if err != nil {
+		return card{}, err
+	}
+	// Teams rejects a TextBlock without text, so empty blocks are left out,
+	// and so is the details block of an event without details.
+	var content []element
+	addText := func(el element) {
+		if strings.TrimSpace(el.Text) != "" {
+			content = append(content, el)
+		}
+	}
+	if n.Templates.Body != nil {
+		text, err := n.Templates.BodyOr(event, "")
+		if err != nil {
+			return card{}, err
+		}
+		addText(element{Type: "TextBlock", Text: text, Wrap: true, Spacing: "Medium"})
+	} else {
+		addText(element{Type: "TextBlock", Text: event.Summary, Wrap: true, Spacing: "Medium"})
+		if strings.TrimSpace(event.Details) != "" || len(event.Items) > 0 {
+			addText(element{Type: "TextBlock", Text: format.EventDetails(event), Wrap: true, IsSubtle: true})
+		}
+	}
+
+	style, color := statusStyle(event.Status)
+	header := element{
+		Type:  "Container",
+		Style: style,
+		Bleed: true,
+		Items: []element{
+			{
+				Type:   "TextBlock",
+				Text:   title,
+				Weight: "Bolder",
+				Size:   "Medium",
+				Color:  color,
+				Wrap:   true,
+			},
+		},
+	}
+	body := append([]element{header}, content...)
+	body = append(body, element{Type: "FactSet", Facts: facts(event), Spacing: "Medium"})
+	return card{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body:    body,
+		MSTeams: map[string]any{"width": "Full"},
+	}, nil
+}
+
+func facts(event notify.Event) []fact {
+	out := []fact{
+		{Title: i18n.T(event.Locale, "label.service"), Value: event.Service},
+		{Title: i18n.T(event.Locale, "label.status"), Value: statusTransition(event)},
+		{Title: i18n.T(event.Locale, "label.time"), Value: event.OccurredAt.Format(time.RFC3339)},
+	}
+	if event.IncidentID != "" {
+		out = append(out, fact{Title: i18n.T(event.Locale, "label.incident"), Value: event.IncidentID})
+	}
+	if !event.StartedAt.IsZero() {
+		out = append(out, fact{Title: i18n.T(event.Locale, "label.started"), Value: event.StartedAt.Format(time.RFC3339)})
+	}
+	if !event.ResolvedAt.IsZero() {
+		out = append(out, fact{Title: i18n.T(event.Locale, "label.resolved"), Value: event.ResolvedAt.Format(time.RFC3339)})
+	}
+	return out
+}
+
+func statusTransition(event notify.Event) string {
+	if event.PreviousStatus == "" || event.PreviousStatus == event.Status {
+		return event.Status
+	}
+	return event.PreviousStatus + " → " + event.Status
+}
+
+// statusStyle maps a status to the container style used as the colour band
+// and the matching text colour for the title.
+func statusStyle(status string) (string, string) {
+	switch strings.ToUpper(status) {
+	case "OK":
+		return "good", "Good"
+	case "WARN":
+		return "warning", "Warning"
+	case "CRIT":
+		return "attention", "Attention"
+	default:
+		return "emphasis", "Default"
+	}
+}
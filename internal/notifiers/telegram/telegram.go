@@ -0,0 +1,246 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"services-health-check/internal/core/i18n"
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/notifiers/format"
+)
+
+const (
+	defaultURL = "https://api.telegram.org"
+
+	ParseModeMarkdownV2 = "MarkdownV2"
+	ParseModeHTML       = "HTML"
+
+	// Telegram rejects messages longer than 4096 characters. The limit is
+	// checked against the escaped text, which can be much longer than the
+	// event's own details.
+	maxMessageRunes = 4096
+)
+
+// Notifier sends messages through the Bot API sendMessage endpoint.
+// URL overrides the API base, e.g. for a self-hosted Bot API server.
+type Notifier struct {
+	NameValue string
+	URL       string
+	BotToken  string
+	ChatID    string
+	ParseMode string
+	Templates format.Templates
+	Timeout   time.Duration
+}
+
+type payload struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode,omitempty"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+type apiResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+func (n *Notifier) Name() string {
+	return n.NameValue
+}
+
+func (n *Notifier) Send(ctx context.Context, event notify.Event) error {
+	if n.BotToken == "" || n.ChatID == "" {
+		return fmt.Errorf("telegram bot token and chat id are required")
+	}
+	mode := normalizeParseMode(n.ParseMode)
+	title, err := n.Templates.TitleOr(event, defaultTitle(event))
+	if err != nil {
+		return err
+	}
+	text, err := n.Templates.BodyOr(event, defaultBody(event))
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(payload{
+		ChatID:                n.ChatID,
+		Text:                  fit(title, text, mode, event.Locale),
+		ParseMode:             mode,
+		DisableWebPagePreview: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	base := n.URL
+	if base == "" {
+		base = defaultURL
+	}
+	endpoint := strings.TrimRight(base, "/") + "/bot" + n.BotToken + "/sendMessage"
+
+	client := &http.Client{Timeout: n.Timeout}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// The request URL embeds the bot token; keep it out of logs.
+		return fmt.Errorf("telegram request failed: %w", redact(err, n.BotToken))
+	}
+	defer resp.Body.Close()
+
+	err = notify.CheckResponse("telegram", resp)
+	var httpErr *notify.HTTPError
+	if errors.As(err, &httpErr) {
+		var r apiResponse
+		if json.Unmarshal([]byte(httpErr.Body), &r) == nil {
+			if r.Description != "" {
+				httpErr.Body = r.Description
+			}
+			if httpErr.RetryAfter == 0 && r.Parameters.RetryAfter > 0 {
+				httpErr.RetryAfter = time.Duration(r.Parameters.RetryAfter) * time.Second
+			}
+		}
+	}
+	return err
+}
+
+// BuildText renders the event with the default wording for the given parse mode.
+func BuildText(event notify.Event, mode string) string {
+	return fit(defaultTitle(event), defaultBody(event), mode, event.Locale)
+}
+
+func defaultTitle(event notify.Event) string {
+	return fmt.Sprintf("[%s] %s", event.Status, event.Service)
+}
+
+func defaultBody(event notify.Event) string {
+	return event.Summary + "\n\n" + format.EventDetails(event)
+}
+
+// fit renders the message and, when the escaped text is over Telegram's
+// limit, cuts body at the last whole line that still fits and marks it as
+// truncated.
+func fit(title, body, mode, locale string) string {
+	text := render(title, body, mode)
+	if utf8.RuneCountInString(text) <= maxMessageRunes {
+		return text
+	}
+	marker := "\n- ..." + i18n.T(locale, "label.truncated")
+	runes := []rune(body)
+	cut := func(n int) string {
+		return render(title, string(runes[:n])+marker, mode)
+	}
+	n := sort.Search(len(runes), func(n int) bool {
+		return utf8.RuneCountInString(cut(n)) > maxMessageRunes
+	}) - 1
+	if n < 0 {
+		// Not even the title fits; Telegram will reject the message.
+		return text
+	}
+	if i := strings.LastIndexByte(string(runes[:n]), '\n'); i > 0 {
+		n = utf8.RuneCountInString(string(runes[:n])[:i])
+	}
+	return cut(n)
+}
+
+// render formats title and body for the parse mode. Backtick spans, such as
+// those produced by the details formatter, become code entities; everything
+// else is escaped so that pod names or domains cannot break the markup.
+func render(title, body, mode string) string {
+	switch normalizeParseMode(mode) {
+	case ParseModeMarkdownV2:
+		return "*" + escapeMarkdown(title) + "*\n" + renderSpans(body, escapeMarkdown, markdownCode)
+	case ParseModeHTML:
+		return "<b>" + html.EscapeString(title) + "</b>\n" + renderSpans(body, html.EscapeString, htmlCode)
+	default:
+		return title + "\n" + body
+	}
+}
+
+func normalizeParseMode(mode string) string {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "markdownv2", "markdown":
+		return ParseModeMarkdownV2
+	case "html":
+		return ParseModeHTML
+	default:
+		return ""
+	}
+}
+
+// renderSpans splits input on backticks and renders odd segments as code.
+// An unmatched trailing backtick is treated as literal text.
+func renderSpans(input string, text, code func(string) string) string {
+	parts := strings.Split(input, "`")
+	if len(parts)%2 == 0 {
+		last := len(parts) - 1
+		parts[last-1] = parts[last-1] + "`" + parts[last]
+		parts = parts[:last]
+	}
+	var b strings.Builder
+	for i, p := range parts {
+		if i%2 == 1 {
+			b.WriteString(code(p))
+			continue
+		}
+		b.WriteString(text(p))
+	}
+	return b.String()
+}
+
+const markdownSpecial = "_*[]()~`>#+-=|{}.!\\"
+
+func escapeMarkdown(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(markdownSpecial, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// markdownCode only needs ` and \ escaped inside a code entity.
+func markdownCode(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "`", "\\`")
+	return "`" + s + "`"
+}
+
+func htmlCode(s string) string {
+	return "<code>" + html.EscapeString(s) + "</code>"
+}
+
+func redact(err error, token string) error {
+	if token == "" {
+		return err
+	}
+	return &redactedError{msg: strings.ReplaceAll(err.Error(), token, "***"), err: err}
+}
+
+// redactedError hides the token in its message but still unwraps to the
+// original error, so retries can tell network failures apart.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
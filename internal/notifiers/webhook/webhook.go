@@ -3,19 +3,33 @@ package webhook
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"text/template"
 	"time"
 
 	"services-health-check/internal/core/notify"
 	"services-health-check/internal/notifiers/format"
 )
 
+const DefaultSignatureHeader = "X-Healthd-Signature"
+
+// Notifier sends the event to an HTTP endpoint. Without a Template the raw
+// event is posted as JSON; with one, the rendered template is the body.
 type Notifier struct {
-	NameValue string
-	URL       string
-	Timeout   time.Duration
+	NameValue  string
+	URL        string
+	Method     string
+	Headers    map[string]string
+	Template   *template.Template
+	HMACSecret string
+	HMACHeader string
+	Timeout    time.Duration
 }
 
 func (n *Notifier) Name() string {
@@ -23,18 +37,32 @@ func (n *Notifier) Name() string {
 }
 
 func (n *Notifier) Send(ctx context.Context, event notify.Event) error {
-	event.Details = format.DetailsList(event.Details)
-	payload, err := json.Marshal(event)
+	payload, err := n.body(event)
 	if err != nil {
 		return err
 	}
 
+	method := strings.ToUpper(strings.TrimSpace(n.Method))
+	if method == "" {
+		method = http.MethodPost
+	}
+
 	client := &http.Client{Timeout: n.Timeout}
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(payload))
+	req, err := http.NewRequestWithContext(ctx, method, n.URL, bytes.NewReader(payload))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	for k, v := range n.Headers {
+		req.Header.Set(k, v)
+	}
+	if n.HMACSecret != "" {
+		header := n.HMACHeader
+		if header == "" {
+			header = DefaultSignatureHeader
+		}
+		req.Header.Set(header, Sign(n.HMACSecret, payload))
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -42,8 +70,24 @@ func (n *Notifier) Send(ctx context.Context, event notify.Event) error {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("webhook status %d", resp.StatusCode)
+	return notify.CheckResponse("webhook", resp)
+}
+
+func (n *Notifier) body(event notify.Event) ([]byte, error) {
+	if n.Template == nil {
+		event.Details = format.DetailsList(event.Details)
+		return json.Marshal(event)
+	}
+	var buf bytes.Buffer
+	if err := n.Template.Execute(&buf, event); err != nil {
+		return nil, fmt.Errorf("render body template: %w", err)
 	}
-	return nil
+	return buf.Bytes(), nil
+}
+
+// Sign returns the "sha256=<hex>" HMAC-SHA256 signature of body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
 }
@@ -0,0 +1,193 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"services-health-check/internal/app"
+	"services-health-check/internal/core/aggregate"
+	"services-health-check/internal/core/notify"
+)
+
+func TestGrouperKeys(t *testing.T) {
+	byType := aggregate.NewGrouper(nil)
+	if got := byType.Key("http", map[string]string{"team": "web"}); got != "http" {
+		t.Fatalf("unexpected key without aggregate_by: %q", got)
+	}
+	if got := byType.Key("", nil); got != "unknown" {
+		t.Fatalf("unexpected key for empty type: %q", got)
+	}
+
+	g := aggregate.NewGrouper([]string{"team", "env"})
+	if got := g.Key("http", map[string]string{"team": "web", "env": "prod", "tier": "1"}); got != "http/web/prod" {
+		t.Fatalf("unexpected key: %q", got)
+	}
+	if got := g.Key("http", map[string]string{"team": "web"}); got != "http/web/" {
+		t.Fatalf("unexpected key for missing label: %q", got)
+	}
+}
+
+func TestGrouperCountsChecksPerGroup(t *testing.T) {
+	g := aggregate.NewGrouper([]string{"team"})
+	g.Expect("http", map[string]string{"team": "web"})
+	g.Expect("http", map[string]string{"team": "web", "env": "prod"})
+	g.Expect("http", map[string]string{"team": "billing"})
+	g.Expect("ssl", map[string]string{"team": "web"})
+
+	want := map[string]int{"http/web": 2, "http/billing": 1, "ssl/web": 1, "ssl/billing": 0}
+	for key, n := range want {
+		if got := g.Expected(key); got != n {
+			t.Fatalf("Expected(%q) = %d, want %d", key, got, n)
+		}
+	}
+}
+
+func TestSharedLabels(t *testing.T) {
+	events := []notify.Event{
+		{Labels: map[string]string{"team": "web", "env": "prod", "status": "CRIT"}},
+		{Labels: map[string]string{"team": "web", "env": "stage", "status": "CRIT"}},
+	}
+	if got := aggregate.SharedLabels(events); !reflect.DeepEqual(got, map[string]string{"team": "web"}) {
+		t.Fatalf("unexpected shared labels: %v", got)
+	}
+	events = append(events, notify.Event{Labels: map[string]string{"env": "prod"}})
+	if got := aggregate.SharedLabels(events); got != nil {
+		t.Fatalf("expected no shared labels, got %v", got)
+	}
+}
+
+func TestAggregatePreviousStatus(t *testing.T) {
+	items := []notify.Event{
+		{Service: "api", Status: "OK", PreviousStatus: "WARN"},
+		{Service: "web", Status: "OK", PreviousStatus: "CRIT"},
+		{Service: "db", Status: "OK"},
+	}
+	if got := aggregate.PreviousStatus(items); got != "CRIT" {
+		t.Fatalf("unexpected previous status: %q", got)
+	}
+	if got := aggregate.PreviousStatus(items[2:]); got != "" {
+		t.Fatalf("expected no previous status, got %q", got)
+	}
+}
+
+func TestRunRoutesAggregatedRecoveryThroughMinStatus(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer target.Close()
+	var ops webhookCapture
+	opsServer := ops.server(t, http.StatusOK)
+
+	path := writeConfig(t, `checks:
+  - type: http
+    name: api
+    url: `+target.URL+`
+    interval: 50ms
+  - type: http
+    name: web
+    url: `+target.URL+`
+    interval: 50ms
+policies:
+  - name: default
+    notify_on_recovery: true
+channels:
+  - type: webhook
+    name: ops
+    url: `+opsServer.URL+`
+routes:
+  - match:
+      min_status: WARN
+    to: [ops]
+notify:
+  aggregate_by_type: true
+  aggregate_window: 1h
+log:
+  level: error
+`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- app.Run(ctx, path) }()
+
+	statuses := func() []string {
+		ops.mu.Lock()
+		defer ops.mu.Unlock()
+		var out []string
+		for _, ev := range ops.events {
+			out = append(out, ev.Status)
+		}
+		return out
+	}
+	waitFor := func(status string) {
+		for ctx.Err() == nil {
+			if got := statuses(); len(got) > 0 && got[len(got)-1] == status {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+	waitFor("CRIT")
+	failing.Store(false)
+	waitFor("OK")
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got := statuses(); !reflect.DeepEqual(got, []string{"CRIT", "OK"}) {
+		t.Fatalf("expected the aggregated failure and recovery on the min_status route, got %v", got)
+	}
+}
+
+func TestRunFlushesAggregatesOnShutdown(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer target.Close()
+	var ops webhookCapture
+	opsServer := ops.server(t, http.StatusOK)
+
+	// Only one of the two checks fails, so the group never completes and the
+	// aggregate is still buffered when the run ends.
+	path := writeConfig(t, `checks:
+  - type: http
+    name: api
+    url: `+target.URL+`/down
+  - type: http
+    name: web
+    url: `+target.URL+`/up
+channels:
+  - type: webhook
+    name: ops
+    url: `+opsServer.URL+`
+routes:
+  - default: true
+    to: [ops]
+notify:
+  aggregate_by_type: true
+  aggregate_window: 1h
+  run_once: true
+log:
+  level: error
+`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := app.Run(ctx, path); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if _, ok := ops.find("http"); !ok {
+		t.Fatalf("expected the buffered aggregate to be sent at shutdown, got %+v", ops.events)
+	}
+}
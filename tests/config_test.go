@@ -0,0 +1,265 @@
+package tests
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"services-health-check/internal/config"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	file, err := os.CreateTemp("", "healthd-*.yaml")
+	if err != nil {
+		t.Fatalf("temp file: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Remove(file.Name()) })
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	_ = file.Close()
+	return file.Name()
+}
+
+func TestLoadNamedPolicies(t *testing.T) {
+	path := writeConfig(t, `checks:
+  - type: http
+    name: web
+    url: http://localhost
+  - type: domain_expiry
+    name: domain
+    domain: example.com
+    policy: daily
+policies:
+  - name: fast
+    cooldown: 5m
+  - name: default
+    cooldown: 10m
+  - name: daily
+    cooldown: 24h
+`)
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	def, ok := cfg.DefaultPolicy()
+	if !ok || def.Name != "default" {
+		t.Fatalf("unexpected default policy: %+v", def)
+	}
+	if cfg.Checks[1].Policy != "daily" {
+		t.Fatalf("unexpected check policy: %q", cfg.Checks[1].Policy)
+	}
+}
+
+func TestLoadUnknownPolicy(t *testing.T) {
+	path := writeConfig(t, `checks:
+  - type: http
+    name: web
+    url: http://localhost
+    policy: missing
+policies:
+  - name: default
+`)
+
+	_, err := config.Load(path)
+	if err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("expected unknown policy error, got %v", err)
+	}
+}
+
+func TestLoadDependencyCycle(t *testing.T) {
+	path := writeConfig(t, `checks:
+  - type: http
+    name: a
+    url: http://localhost
+    depends_on: [b]
+  - type: http
+    name: b
+    url: http://localhost
+    depends_on: [a]
+`)
+
+	_, err := config.Load(path)
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("expected dependency cycle error, got %v", err)
+	}
+}
+
+func TestLoadEscalationRequiresAfter(t *testing.T) {
+	path := writeConfig(t, `routes:
+  - match:
+      status: CRIT
+    to: [discord]
+    escalations:
+      - to: [smtp]
+`)
+
+	_, err := config.Load(path)
+	if err == nil || !strings.Contains(err.Error(), "after") {
+		t.Fatalf("expected escalation error, got %v", err)
+	}
+}
+
+func TestLoadRejectsUnsupportedLocale(t *testing.T) {
+	path := writeConfig(t, `locale: en
+checks:
+  - type: http
+    name: web
+    url: http://localhost
+channels:
+  - type: slack
+    name: partner
+    url: http://localhost
+    locale: fr
+`)
+	_, err := config.Load(path)
+	if err == nil || !strings.Contains(err.Error(), "unsupported locale") {
+		t.Fatalf("expected unsupported locale error, got %v", err)
+	}
+}
+
+func TestLoadRejectsUnknownQueueOverflow(t *testing.T) {
+	path := writeConfig(t, `checks:
+  - type: http
+    name: web
+    url: http://localhost
+channels:
+  - type: slack
+    name: alerts
+    url: http://localhost
+    queue_overflow: wait
+`)
+	_, err := config.Load(path)
+	if err == nil || !strings.Contains(err.Error(), "queue_overflow") {
+		t.Fatalf("expected queue_overflow error, got %v", err)
+	}
+}
+
+func TestLoadRejectsShortFlapWindow(t *testing.T) {
+	path := writeConfig(t, `checks:
+  - type: http
+    name: web
+    url: http://localhost
+policies:
+  - name: rollout
+    flap_window: 2
+`)
+	_, err := config.Load(path)
+	if err == nil || !strings.Contains(err.Error(), "flap_window") {
+		t.Fatalf("expected flap_window error, got %v", err)
+	}
+}
+
+func TestLoadRejectsFallbackCycle(t *testing.T) {
+	path := writeConfig(t, `checks:
+  - type: http
+    name: web
+    url: http://localhost
+channels:
+  - type: slack
+    name: a
+    url: http://localhost
+    fallback: b
+  - type: slack
+    name: b
+    url: http://localhost
+    fallback: a
+`)
+	_, err := config.Load(path)
+	if err == nil || !strings.Contains(err.Error(), "fallback cycle") {
+		t.Fatalf("expected fallback cycle error, got %v", err)
+	}
+}
+
+func TestLoadRejectsSecondDefaultRoute(t *testing.T) {
+	path := writeConfig(t, `checks:
+  - type: http
+    name: web
+    url: http://localhost
+routes:
+  - default: true
+    to: [a]
+  - match:
+      min_status: WARN
+    to: [b]
+  - default: true
+    to: [c]
+`)
+	_, err := config.Load(path)
+	if err == nil || !strings.Contains(err.Error(), "only one default route") {
+		t.Fatalf("expected default route error, got %v", err)
+	}
+}
+
+func TestLoadRejectsUnknownMinStatus(t *testing.T) {
+	path := writeConfig(t, `checks:
+  - type: http
+    name: web
+    url: http://localhost
+routes:
+  - match:
+      min_status: SEVERE
+    to: [a]
+`)
+	_, err := config.Load(path)
+	if err == nil || !strings.Contains(err.Error(), "min_status") {
+		t.Fatalf("expected min_status error, got %v", err)
+	}
+}
+
+func TestLoadCheckLabels(t *testing.T) {
+	t.Setenv("NOTIFY_AGGREGATE_BY", "team, env")
+	path := writeConfig(t, `checks:
+  - type: http
+    name: web
+    url: http://localhost
+    labels:
+      team: platform
+      env: prod
+`)
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := cfg.Checks[0].Labels; got["team"] != "platform" || got["env"] != "prod" {
+		t.Fatalf("unexpected labels: %v", got)
+	}
+	if got := cfg.Notify.AggregateBy; len(got) != 2 || got[0] != "team" || got[1] != "env" {
+		t.Fatalf("unexpected aggregate_by: %v", got)
+	}
+}
+
+func TestLoadRejectsReservedLabel(t *testing.T) {
+	path := writeConfig(t, `checks:
+  - type: http
+    name: web
+    url: http://localhost
+    labels:
+      status: fine
+`)
+	_, err := config.Load(path)
+	if err == nil || !strings.Contains(err.Error(), "reserved label") {
+		t.Fatalf("expected reserved label error, got %v", err)
+	}
+}
+
+func TestLoadRejectsUnknownEscalationChannel(t *testing.T) {
+	path := writeConfig(t, `channels:
+  - type: discord
+    name: discord
+    url: http://localhost
+routes:
+  - match:
+      status: CRIT
+    to: [discord]
+    escalations:
+      - after: 15m
+        to: [smtp-oncall]
+`)
+	_, err := config.Load(path)
+	if err == nil || !strings.Contains(err.Error(), `unknown channel at route index 0 escalation 0: "smtp-oncall"`) {
+		t.Fatalf("expected unknown escalation channel error, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpcheck "services-health-check/internal/checkers/http"
+	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/i18n"
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/notifiers/discord"
+	"services-health-check/internal/notifiers/format"
+	"services-health-check/internal/notifiers/slack"
+	"services-health-check/internal/notifiers/smtp"
+)
+
+func sampleItems() []check.Detail {
+	return []check.Detail{
+		{Label: i18n.Text("Domain"), Value: "example.com", Kind: check.KindDomain},
+		{Label: i18n.Text("Unready"), Children: []check.Detail{
+			{Value: "default/api; v2", Kind: check.KindPod},
+			{Value: "default/worker", Kind: check.KindPod},
+		}},
+	}
+}
+
+func TestItemsList(t *testing.T) {
+	got := format.ItemsList(sampleItems())
+	want := "- Domain: `example.com`\n- Unready\n  - `default/api; v2`\n  - `default/worker`"
+	if got != want {
+		t.Fatalf("unexpected list: got %q want %q", got, want)
+	}
+}
+
+func TestEventDetailsWithItems(t *testing.T) {
+	event := notify.Event{Details: "Ready 1/3", Items: sampleItems()[:1]}
+	got := format.EventDetails(event)
+	want := "- Ready 1/3\n- Domain: `example.com`"
+	if got != want {
+		t.Fatalf("unexpected details: got %q want %q", got, want)
+	}
+
+	event = notify.Event{Details: "a; b"}
+	if got := format.EventDetails(event); got != "- a\n- b" {
+		t.Fatalf("unexpected legacy details: %q", got)
+	}
+}
+
+func TestSlackItemFields(t *testing.T) {
+	var got struct {
+		Attachments []struct {
+			Blocks []struct {
+				Type   string `json:"type"`
+				Fields []struct {
+					Text string `json:"text"`
+				} `json:"fields"`
+			} `json:"blocks"`
+		} `json:"attachments"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	items := sampleItems()
+	for i := 0; i < 10; i++ {
+		items = append(items, check.Detail{Label: i18n.Text("n"), Value: "v"})
+	}
+	n := &slack.Notifier{NameValue: "slack", URL: server.URL, Timeout: 2 * time.Second}
+	event := notify.Event{Service: "svc", Status: "CRIT", Summary: "sum", Items: items, OccurredAt: time.Now()}
+	if err := n.Send(context.Background(), event); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+
+	var fields []string
+	sections := 0
+	for _, b := range got.Attachments[0].Blocks {
+		if len(b.Fields) > 0 {
+			sections++
+			for _, f := range b.Fields {
+				fields = append(fields, f.Text)
+			}
+		}
+	}
+	if sections != 2 || len(fields) != 12 {
+		t.Fatalf("expected 12 fields in 2 sections, got %d in %d", len(fields), sections)
+	}
+	if fields[0] != "*Domain*\n`example.com`" {
+		t.Fatalf("unexpected field: %q", fields[0])
+	}
+	if fields[1] != "*Unready*\n• `default/api; v2`\n• `default/worker`" {
+		t.Fatalf("unexpected field: %q", fields[1])
+	}
+}
+
+func TestDiscordItemFields(t *testing.T) {
+	var got struct {
+		Embeds []struct {
+			Fields []struct {
+				Name   string `json:"name"`
+				Value  string `json:"value"`
+				Inline bool   `json:"inline"`
+			} `json:"fields"`
+		} `json:"embeds"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := &discord.Notifier{NameValue: "discord", URL: server.URL, Timeout: 2 * time.Second}
+	event := notify.Event{Service: "svc", Status: "WARN", Summary: "sum", Details: "Ready 1/3", Items: sampleItems(), Locale: "en", OccurredAt: time.Now()}
+	if err := n.Send(context.Background(), event); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	fields := got.Embeds[0].Fields
+	if len(fields) != 3 {
+		t.Fatalf("expected 3 fields, got %+v", fields)
+	}
+	if fields[0].Name != "Details" || fields[0].Value != "Ready 1/3" {
+		t.Fatalf("unexpected message field: %+v", fields[0])
+	}
+	if fields[1].Name != "Domain" || fields[1].Value != "`example.com`" || !fields[1].Inline {
+		t.Fatalf("unexpected domain field: %+v", fields[1])
+	}
+	if fields[2].Name != "Unready" || fields[2].Inline || !strings.Contains(fields[2].Value, "- `default/api; v2`") {
+		t.Fatalf("unexpected unready field: %+v", fields[2])
+	}
+}
+
+func TestIncidentFieldsFollowChannelLocale(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		body = string(raw)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	event := notify.Event{
+		Service: "svc", Status: "CRIT", Summary: "down", Details: "boom", Locale: "zh-TW",
+		IncidentID: "inc-1", DedupKey: "healthd/svc", StartedAt: now, OccurredAt: now,
+	}
+	notifiers := []notify.Notifier{
+		&discord.Notifier{NameValue: "discord", URL: server.URL, Timeout: 2 * time.Second},
+		&slack.Notifier{NameValue: "slack", URL: server.URL, Timeout: 2 * time.Second},
+	}
+	for _, n := range notifiers {
+		if err := n.Send(context.Background(), event); err != nil {
+			t.Fatalf("%s: send error: %v", n.Name(), err)
+		}
+		for _, key := range []string{"label.incident", "label.started", "label.dedup"} {
+			if want := i18n.T("zh-TW", key); !strings.Contains(body, want) {
+				t.Fatalf("%s: missing %q in %s", n.Name(), want, body)
+			}
+		}
+		for _, english := range []string{"Incident", "Started", "Dedup"} {
+			if strings.Contains(body, english) {
+				t.Fatalf("%s: untranslated %q in %s", n.Name(), english, body)
+			}
+		}
+	}
+}
+
+func TestSMTPBuildHTML(t *testing.T) {
+	event := notify.Event{
+		Service:    "svc",
+		Status:     "CRIT",
+		Summary:    "<down>",
+		Items:      append(sampleItems(), check.Detail{Label: i18n.Text("URL"), Value: "https://a.test/?x=1&y=2", Kind: check.KindURL}),
+		OccurredAt: time.Now(),
+	}
+	got := smtp.BuildHTML(event)
+	for _, want := range []string{
+		"<p>&lt;down&gt;</p>",
+		"<th align=\"left\">Domain</th><td><code>example.com</code></td>",
+		"<li><code>default/api; v2</code></li>",
+		"<a href=\"https://a.test/?x=1&amp;y=2\">",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestHTTPCheckerDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	checker := &httpcheck.Checker{NameValue: "http", URL: server.URL, Timeout: 2 * time.Second}
+	res, err := checker.Check(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Details) != 2 {
+		t.Fatalf("expected 2 details, got %+v", res.Details)
+	}
+	if res.Details[0].Kind != check.KindURL || res.Details[0].Value != server.URL {
+		t.Fatalf("unexpected url detail: %+v", res.Details[0])
+	}
+	if res.Details[1].Value != "503 Service Unavailable" {
+		t.Fatalf("unexpected status detail: %+v", res.Details[1])
+	}
+}
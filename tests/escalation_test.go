@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/escalation"
+	"services-health-check/internal/core/notify"
+)
+
+type fakeStatuses map[string]check.Status
+
+func (f fakeStatuses) Status(name string) (check.Status, bool) {
+	s, ok := f[name]
+	return s, ok
+}
+
+var escalationSteps = []escalation.Step{
+	{After: 15 * time.Minute, To: []string{"oncall"}},
+	{After: time.Hour, To: []string{"manager"}},
+}
+
+func critEvent(at time.Time) notify.Event {
+	return notify.Event{Service: "api", Status: "CRIT", PreviousStatus: "OK", OccurredAt: at}
+}
+
+func dueTargets(items []escalation.Pending) []string {
+	var out []string
+	for _, p := range items {
+		out = append(out, p.Step.To...)
+	}
+	return out
+}
+
+func TestEscalationDueAfterEachStep(t *testing.T) {
+	start := time.Date(2026, 1, 10, 1, 0, 0, 0, time.UTC)
+	tr := escalation.NewTracker(fakeStatuses{"api": check.StatusCrit})
+	tr.Track(critEvent(start), escalationSteps)
+
+	if got := tr.Due(start.Add(14 * time.Minute)); len(got) != 0 {
+		t.Fatalf("expected nothing before after, got %+v", got)
+	}
+	if got := dueTargets(tr.Due(start.Add(15 * time.Minute))); len(got) != 1 || got[0] != "oncall" {
+		t.Fatalf("expected first step at 15m, got %v", got)
+	}
+	if got := tr.Due(start.Add(30 * time.Minute)); len(got) != 0 {
+		t.Fatalf("expected the first step to fire once, got %+v", got)
+	}
+	if got := dueTargets(tr.Due(start.Add(2 * time.Hour))); len(got) != 1 || got[0] != "manager" {
+		t.Fatalf("expected second step at 1h, got %v", got)
+	}
+	if got := tr.Due(start.Add(3 * time.Hour)); len(got) != 0 {
+		t.Fatalf("expected every step to fire once, got %+v", got)
+	}
+}
+
+func TestEscalationCancelledOnRecovery(t *testing.T) {
+	start := time.Date(2026, 1, 10, 1, 0, 0, 0, time.UTC)
+	tr := escalation.NewTracker(nil)
+	tr.Track(critEvent(start), escalationSteps)
+	tr.Track(notify.Event{Service: "api", Status: "OK", PreviousStatus: "CRIT", OccurredAt: start.Add(time.Minute)}, nil)
+	if got := tr.Due(start.Add(2 * time.Hour)); len(got) != 0 {
+		t.Fatalf("expected recovery to cancel escalations, got %+v", got)
+	}
+
+	// The policy reporting the check as no longer CRIT cancels them too.
+	statuses := fakeStatuses{"api": check.StatusCrit}
+	tr = escalation.NewTracker(statuses)
+	tr.Track(critEvent(start), escalationSteps)
+	statuses["api"] = check.StatusWarn
+	if got := tr.Due(start.Add(2 * time.Hour)); len(got) != 0 {
+		t.Fatalf("expected escalations to stop once the check left CRIT, got %+v", got)
+	}
+}
+
+func TestEscalationNotRearmedWhileCritical(t *testing.T) {
+	start := time.Date(2026, 1, 10, 1, 0, 0, 0, time.UTC)
+	tr := escalation.NewTracker(nil)
+	tr.Track(critEvent(start), escalationSteps)
+
+	// A second opening CRIT, e.g. from another route, keeps the first timers.
+	tr.Track(critEvent(start.Add(10*time.Minute)), escalationSteps)
+	if got := tr.Due(start.Add(15 * time.Minute)); len(got) != 1 {
+		t.Fatalf("expected the original timer to fire, got %+v", got)
+	}
+
+	// Reminders while still CRIT do not arm new steps once all have fired.
+	_ = tr.Due(start.Add(time.Hour))
+	reminder := notify.Event{Service: "api", Status: "CRIT", PreviousStatus: "CRIT", OccurredAt: start.Add(2 * time.Hour)}
+	tr.Track(reminder, escalationSteps)
+	if got := tr.Due(start.Add(5 * time.Hour)); len(got) != 0 {
+		t.Fatalf("expected no re-armed escalation, got %+v", got)
+	}
+}
@@ -20,3 +20,11 @@ func TestDetailsListEmpty(t *testing.T) {
 		t.Fatalf("unexpected empty output: %q", got)
 	}
 }
+
+func TestDetailsListEnglishExamples(t *testing.T) {
+	got := format.DetailsList("Ready 1/3; e.g.: default/api; default/worker")
+	want := "- Ready 1/3\n - `default/api`\n - `default/worker`"
+	if got != want {
+		t.Fatalf("unexpected list: got %q want %q", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/i18n"
+	"services-health-check/internal/core/policy"
+)
+
+func TestI18nRenderLocales(t *testing.T) {
+	msg := i18n.M("escalation.summary", i18n.M("k8s.ready", 1, 3), "30m0s")
+	if got := i18n.Render("zh-TW", msg); got != "[升級] 就緒 1/3（CRIT 超過 30m0s 未恢復）" {
+		t.Fatalf("unexpected zh-TW: %q", got)
+	}
+	if got := i18n.Render("en_US", msg); got != "[Escalated] Ready 1/3 (CRIT for more than 30m0s)" {
+		t.Fatalf("unexpected en: %q", got)
+	}
+	list := i18n.List{"a", i18n.M("ssl.expired")}.Message()
+	if got := i18n.Render("en", list); got != "a; Certificate has expired" {
+		t.Fatalf("unexpected list: %q", got)
+	}
+	if got := i18n.Render("fr", i18n.M("ssl.expired")); got != "憑證已過期" {
+		t.Fatalf("unknown locale should fall back to default: %q", got)
+	}
+	if got := i18n.Render("en", i18n.M("no.such.key", "x")); got != "no.such.key x" {
+		t.Fatalf("unexpected missing key output: %q", got)
+	}
+}
+
+func TestEventLocalize(t *testing.T) {
+	p := policy.NewSimplePolicy(0, true)
+	ev, err := p.Evaluate(context.Background(), check.Result{
+		Name:    "cert",
+		Status:  check.StatusWarn,
+		Message: i18n.M("ssl.expiring", "3d0h0m0s"),
+	})
+	if err != nil || ev == nil {
+		t.Fatalf("expected event, got %v, %v", ev, err)
+	}
+	if !strings.Contains(ev.Summary, "狀態") || ev.Details != "憑證即將過期：3d0h0m0s" {
+		t.Fatalf("default locale should be zh-TW: %q / %q", ev.Summary, ev.Details)
+	}
+
+	en := ev.Localize("en")
+	if en.Locale != "en" || en.Summary != "cert status: OK → WARN" || en.Details != "Certificate expires soon: 3d0h0m0s" {
+		t.Fatalf("unexpected english event: %+v", en)
+	}
+	if ev.Details != "憑證即將過期：3d0h0m0s" {
+		t.Fatalf("localize must not modify the original event")
+	}
+}
@@ -0,0 +1,175 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/core/outbox"
+	"services-health-check/internal/notifiers/line"
+)
+
+type linePayload struct {
+	To       string `json:"to"`
+	Messages []struct {
+		Type     string `json:"type"`
+		Text     string `json:"text"`
+		AltText  string `json:"altText"`
+		Contents *struct {
+			Header struct {
+				BackgroundColor string `json:"backgroundColor"`
+			} `json:"header"`
+		} `json:"contents"`
+	} `json:"messages"`
+}
+
+func TestLinePushFlex(t *testing.T) {
+	var mu sync.Mutex
+	var got []linePayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var p linePayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		got = append(got, p)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := &line.Notifier{
+		NameValue: "line",
+		URL:       server.URL,
+		Token:     "token",
+		To:        []string{"U1", "C2"},
+		Flex:      true,
+		Timeout:   2 * time.Second,
+	}
+	event := notify.Event{Service: "svc", Status: "CRIT", Summary: "服務異常", Details: "a; b", OccurredAt: time.Now()}
+	if err := n.Send(context.Background(), event); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if len(got) != 2 || got[0].To != "U1" || got[1].To != "C2" {
+		t.Fatalf("unexpected pushes: %+v", got)
+	}
+	msg := got[0].Messages[0]
+	if msg.Type != "flex" || msg.AltText == "" || msg.Contents == nil || msg.Contents.Header.BackgroundColor != "#E74C3C" {
+		t.Fatalf("unexpected flex message: %+v", msg)
+	}
+}
+
+func TestLinePushText(t *testing.T) {
+	var got linePayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := &line.Notifier{NameValue: "line", URL: server.URL, Token: "token", To: []string{"U1"}, Timeout: 2 * time.Second}
+	event := notify.Event{Service: "svc", Status: "WARN", Summary: "sum", Details: "example.com", OccurredAt: time.Now()}
+	if err := n.Send(context.Background(), event); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if len(got.Messages) != 1 || got.Messages[0].Type != "text" || got.Messages[0].Text == "" {
+		t.Fatalf("unexpected text message: %+v", got)
+	}
+}
+
+func TestLineRetriesOnlyFailedRecipient(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p linePayload
+		_ = json.NewDecoder(r.Body).Decode(&p)
+		mu.Lock()
+		calls[p.To]++
+		n := calls[p.To]
+		mu.Unlock()
+		if p.To == "U2" && n == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := &line.Notifier{NameValue: "line", URL: server.URL, Token: "token", To: []string{"U1", "U2"}, Timeout: 2 * time.Second}
+	event := notify.Event{Service: "svc", Status: "CRIT", OccurredAt: time.Now()}
+
+	err := n.Send(context.Background(), event)
+	if !notify.Transient(err) {
+		t.Fatalf("expected transient typed error, got %v", err)
+	}
+
+	calls = make(map[string]int)
+	r := notify.NewRetry(n, 3, time.Millisecond, time.Millisecond)
+	if err := r.Send(context.Background(), event); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if calls["U1"] != 1 || calls["U2"] != 2 {
+		t.Fatalf("unexpected pushes per recipient: %v", calls)
+	}
+}
+
+func TestLineMixedFailuresRetryOnlyFailedRecipients(t *testing.T) {
+	var mu sync.Mutex
+	failing := map[string]int{"U1": http.StatusBadRequest, "U2": http.StatusServiceUnavailable}
+	calls := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p linePayload
+		_ = json.NewDecoder(r.Body).Decode(&p)
+		mu.Lock()
+		defer mu.Unlock()
+		calls[p.To]++
+		if code, ok := failing[p.To]; ok {
+			w.WriteHeader(code)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := &line.Notifier{NameValue: "line", URL: server.URL, Token: "token", To: []string{"U1", "U2", "U3"}, Timeout: 2 * time.Second}
+	event := notify.Event{Service: "svc", Status: "CRIT", OccurredAt: time.Now()}
+
+	err := n.Send(context.Background(), event)
+	if !notify.Transient(err) {
+		t.Fatalf("a batch with a 503 should be transient: %v", err)
+	}
+	if got := notify.FailedRecipients(err); len(got) != 2 || got[0] != "U1" || got[1] != "U2" {
+		t.Fatalf("unexpected failed recipients: %v", got)
+	}
+
+	box := outbox.New(
+		outbox.NewStore(filepath.Join(t.TempDir(), "outbox.jsonl")),
+		outbox.NewStore(filepath.Join(t.TempDir(), "outbox.dead.jsonl")),
+		3,
+		map[string]notify.Notifier{"line": n},
+	)
+	if err := box.Add("line", event, err); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	mu.Lock()
+	failing = nil
+	calls = make(map[string]int)
+	mu.Unlock()
+	if res, err := box.Flush(context.Background()); err != nil || res.Sent != 1 {
+		t.Fatalf("unexpected flush: %+v %v", res, err)
+	}
+	if calls["U1"] != 1 || calls["U2"] != 1 || calls["U3"] != 0 {
+		t.Fatalf("the outbox should only re-send to failed recipients: %v", calls)
+	}
+}
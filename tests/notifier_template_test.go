@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"services-health-check/internal/core/i18n"
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/notifiers/discord"
+	"services-health-check/internal/notifiers/format"
+	"services-health-check/internal/notifiers/gchat"
+)
+
+func TestGChatTemplates(t *testing.T) {
+	var got struct {
+		Text string `json:"text"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tmpls, err := format.ParseTemplates("gchat", `{{ .Service }} is {{ lower .Status }}`, `{{ .Summary }} ({{ .Type }})`)
+	if err != nil {
+		t.Fatalf("parse templates: %v", err)
+	}
+	n := &gchat.Notifier{NameValue: "gchat", URL: server.URL, Templates: tmpls, Timeout: 2 * time.Second}
+	event := notify.Event{Service: "svc", Type: "http", Status: "CRIT", Summary: "down", OccurredAt: time.Now()}
+	if err := n.Send(context.Background(), event); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if got.Text != "svc is crit\ndown (http)" {
+		t.Fatalf("unexpected text: %q", got.Text)
+	}
+}
+
+func TestDiscordTemplatesReplaceDetails(t *testing.T) {
+	var got discordPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tmpls, err := format.ParseTemplates("discord", `{{ .Status }} · {{ .Service }}`, `{{ details .Details }}`)
+	if err != nil {
+		t.Fatalf("parse templates: %v", err)
+	}
+	n := &discord.Notifier{NameValue: "discord", URL: server.URL, Templates: tmpls, Timeout: 2 * time.Second}
+	event := notify.Event{Service: "svc", Status: "WARN", Summary: "sum", Details: "a; b", OccurredAt: time.Now()}
+	if err := n.Send(context.Background(), event); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if len(got.Embeds) == 0 || got.Embeds[0].Title != "WARN · svc" {
+		t.Fatalf("unexpected title: %+v", got)
+	}
+	for _, f := range got.Embeds[0].Fields {
+		if f.Name == i18n.T("", "label.details") {
+			t.Fatalf("details field should be owned by the body template")
+		}
+	}
+}
+
+func TestDefaultTemplatesKeepBuiltInFormat(t *testing.T) {
+	event := notify.Event{Service: "svc", Status: "OK", Summary: "fine"}
+	var tmpls format.Templates
+	title, err := tmpls.TitleOr(event, "[OK] svc")
+	if err != nil || title != "[OK] svc" {
+		t.Fatalf("unexpected fallback: %q, %v", title, err)
+	}
+	if _, err := format.ParseTemplates("bad", "{{ .Service ", ""); err == nil {
+		t.Fatalf("expected parse error")
+	}
+}
+
+func TestTemplatesReadLabels(t *testing.T) {
+	tmpls, err := format.ParseTemplates("labels", "", `{{ .Labels.team }}/{{ default "none" .Labels.env }}`)
+	if err != nil {
+		t.Fatalf("parse templates: %v", err)
+	}
+	out, err := tmpls.BodyOr(notify.Event{Labels: map[string]string{"team": "platform"}}, "")
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if out != "platform/none" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+}
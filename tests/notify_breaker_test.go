@@ -0,0 +1,236 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"services-health-check/internal/app"
+	"services-health-check/internal/core/notify"
+)
+
+// recordingNotifier fails while err is set and records delivered events.
+type recordingNotifier struct {
+	mu   sync.Mutex
+	name string
+	err  error
+	sent []string
+}
+
+func (n *recordingNotifier) Name() string { return n.name }
+
+func (n *recordingNotifier) Send(ctx context.Context, event notify.Event) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, event.Service)
+	return nil
+}
+
+func (n *recordingNotifier) setErr(err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.err = err
+}
+
+func TestBreakerOpensAndFallsBack(t *testing.T) {
+	primary := &recordingNotifier{name: "discord", err: &notify.HTTPError{Channel: "discord", StatusCode: 404}}
+	fallback := &recordingNotifier{name: "mail"}
+	b := notify.NewBreaker(primary, fallback, 2, time.Minute)
+	now := time.Date(2026, 1, 10, 1, 0, 0, 0, time.UTC)
+	b.Now = func() time.Time { return now }
+	var states []notify.BreakerState
+	b.OnStateChange = func(state notify.BreakerState, err error) {
+		states = append(states, state)
+	}
+
+	if err := b.Send(context.Background(), notify.Event{Service: "a"}); err == nil {
+		t.Fatalf("expected the first failure to be returned")
+	}
+	// The second failure opens the circuit and the event goes to the fallback.
+	if err := b.Send(context.Background(), notify.Event{Service: "b"}); err != nil {
+		t.Fatalf("expected fallback delivery, got %v", err)
+	}
+	if b.State() != notify.BreakerOpen || len(states) != 1 || states[0] != notify.BreakerOpen {
+		t.Fatalf("expected open circuit, got %s %v", b.State(), states)
+	}
+	primary.setErr(nil)
+	if err := b.Send(context.Background(), notify.Event{Service: "c"}); err != nil {
+		t.Fatalf("send while open: %v", err)
+	}
+	if len(primary.sent) != 0 {
+		t.Fatalf("primary called while open: %v", primary.sent)
+	}
+
+	now = now.Add(time.Minute)
+	if err := b.Send(context.Background(), notify.Event{Service: "d"}); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if b.State() != notify.BreakerClosed || len(states) != 2 || states[1] != notify.BreakerClosed {
+		t.Fatalf("expected closed circuit, got %s %v", b.State(), states)
+	}
+	if len(fallback.sent) != 2 || fallback.sent[0] != "b" || fallback.sent[1] != "c" {
+		t.Fatalf("unexpected fallback deliveries: %v", fallback.sent)
+	}
+	if len(primary.sent) != 1 || primary.sent[0] != "d" {
+		t.Fatalf("unexpected primary deliveries: %v", primary.sent)
+	}
+}
+
+func TestBreakerWithoutFallback(t *testing.T) {
+	primary := &recordingNotifier{name: "slack", err: errors.New("boom")}
+	b := notify.NewBreaker(primary, nil, 1, time.Minute)
+	_ = b.Send(context.Background(), notify.Event{})
+	if err := b.Send(context.Background(), notify.Event{}); !errors.Is(err, notify.ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+}
+
+func TestBreakerFailedProbeReopens(t *testing.T) {
+	primary := &recordingNotifier{name: "slack", err: errors.New("boom")}
+	b := notify.NewBreaker(primary, nil, 1, time.Minute)
+	now := time.Date(2026, 1, 10, 1, 0, 0, 0, time.UTC)
+	b.Now = func() time.Time { return now }
+	_ = b.Send(context.Background(), notify.Event{})
+	now = now.Add(59 * time.Second)
+	if b.State() != notify.BreakerOpen {
+		t.Fatalf("expected open during cooldown, got %s", b.State())
+	}
+	now = now.Add(time.Second)
+	if b.State() != notify.BreakerHalfOpen {
+		t.Fatalf("expected half-open after cooldown, got %s", b.State())
+	}
+	if err := b.Send(context.Background(), notify.Event{}); !errors.Is(err, notify.ErrCircuitOpen) {
+		t.Fatalf("expected failed probe to reopen, got %v", err)
+	}
+	if b.State() != notify.BreakerOpen {
+		t.Fatalf("expected open, got %s", b.State())
+	}
+}
+
+type webhookCapture struct {
+	mu     sync.Mutex
+	events []struct {
+		Service string
+		Type    string
+		Status  string
+		Items   []struct{ Label, Value string }
+	}
+}
+
+func (c *webhookCapture) server(t *testing.T, status int) *httptest.Server {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev struct {
+			Service string
+			Type    string
+			Status  string
+			Items   []struct{ Label, Value string }
+		}
+		if err := json.NewDecoder(r.Body).Decode(&ev); err == nil {
+			c.mu.Lock()
+			c.events = append(c.events, ev)
+			c.mu.Unlock()
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// find returns the labels of the first event of service, if any.
+func (c *webhookCapture) find(service string) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, ev := range c.events {
+		if ev.Service != service {
+			continue
+		}
+		var labels []string
+		for _, item := range ev.Items {
+			labels = append(labels, item.Label)
+		}
+		return labels, true
+	}
+	return nil, false
+}
+
+func TestRunDivertsToFallbackWhenCircuitOpens(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer target.Close()
+	var primary, backup, ops webhookCapture
+	primaryServer := primary.server(t, http.StatusBadGateway)
+	backupServer := backup.server(t, http.StatusOK)
+	opsServer := ops.server(t, http.StatusOK)
+
+	path := writeConfig(t, `checks:
+  - type: http
+    name: api
+    url: `+target.URL+`
+    interval: 50ms
+channels:
+  - type: webhook
+    name: primary
+    url: `+primaryServer.URL+`
+    fallback: backup
+  - type: webhook
+    name: backup
+    url: `+backupServer.URL+`
+    locale: en
+  - type: webhook
+    name: ops
+    url: `+opsServer.URL+`
+routes:
+  - match:
+      name: api
+    to: [primary]
+  - match:
+      type: channel
+    to: [ops]
+notify:
+  retry_attempts: 1
+  breaker_failures: 1
+log:
+  level: error
+`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- app.Run(ctx, path) }()
+
+	delivered := func() bool {
+		_, forwarded := backup.find("api")
+		_, alerted := backup.find("primary")
+		_, routed := ops.find("primary")
+		return forwarded && alerted && routed
+	}
+	for !delivered() && ctx.Err() == nil {
+		time.Sleep(20 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !delivered() {
+		t.Fatalf("expected the event and the meta-alert on backup and the meta-alert on ops; backup=%+v ops=%+v", backup.events, ops.events)
+	}
+
+	// The forwarded event is rendered in the fallback's locale.
+	labels, _ := backup.find("api")
+	if len(labels) != 2 || labels[0] != "URL" || labels[1] != "HTTP status" {
+		t.Fatalf("expected English item labels on the fallback, got %q", labels)
+	}
+	if _, ok := ops.find("api"); ok {
+		t.Fatalf("the check event should not reach ops")
+	}
+}
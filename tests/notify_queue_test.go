@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"services-health-check/internal/core/notify"
+)
+
+// blockingNotifier waits on release before every send.
+type blockingNotifier struct {
+	release chan struct{}
+	sent    atomic.Int32
+}
+
+func (n *blockingNotifier) Name() string { return "blocking" }
+
+func (n *blockingNotifier) Send(ctx context.Context, event notify.Event) error {
+	select {
+	case <-n.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	n.sent.Add(1)
+	return nil
+}
+
+func TestQueueSendDoesNotWaitForDelivery(t *testing.T) {
+	n := &blockingNotifier{release: make(chan struct{})}
+	q := notify.NewQueue(n, 1, 1, notify.OverflowDrop)
+
+	start := time.Now()
+	_ = q.Send(context.Background(), notify.Event{Service: "svc"})
+	// Wait for the worker to pick up the first event and hang on it.
+	deadline := time.Now().Add(time.Second)
+	for q.Stats().Depth > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := q.Send(context.Background(), notify.Event{Service: "svc"}); err != nil {
+		t.Fatalf("expected the buffer to take the second event, got %v", err)
+	}
+	if err := q.Send(context.Background(), notify.Event{Service: "svc"}); !errors.Is(err, notify.ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("send blocked on a hanging notifier")
+	}
+
+	close(n.release)
+	if err := q.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	s := q.Stats()
+	if s.Delivered != 2 || n.sent.Load() != 2 {
+		t.Fatalf("expected 2 delivered, got %+v (sent %d)", s, n.sent.Load())
+	}
+	if s.Dropped != 1 {
+		t.Fatalf("expected 1 dropped, got %+v", s)
+	}
+}
+
+func TestQueueBlockOverflow(t *testing.T) {
+	n := &blockingNotifier{release: make(chan struct{})}
+	q := notify.NewQueue(n, 1, 1, notify.OverflowBlock)
+	_ = q.Send(context.Background(), notify.Event{})
+	_ = q.Send(context.Background(), notify.Event{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	// The worker may not have taken the first event yet; keep sending until
+	// the buffer is full and the send has to wait.
+	var err error
+	for i := 0; i < 3 && err == nil; i++ {
+		err = q.Send(ctx, notify.Event{})
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected back-pressure to end with the context, got %v", err)
+	}
+	if q.Stats().Blocked == 0 {
+		t.Fatalf("expected blocked counter, got %+v", q.Stats())
+	}
+	close(n.release)
+	_ = q.Close(context.Background())
+}
+
+func TestQueueCloseDrainsAndTimesOut(t *testing.T) {
+	n := &blockingNotifier{release: make(chan struct{})}
+	q := notify.NewQueue(n, 10, 1, "")
+	for i := 0; i < 3; i++ {
+		if err := q.Send(context.Background(), notify.Event{}); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := q.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected drain timeout, got %v", err)
+	}
+	if err := q.Send(context.Background(), notify.Event{}); !errors.Is(err, notify.ErrQueueClosed) {
+		t.Fatalf("expected ErrQueueClosed, got %v", err)
+	}
+	if n.sent.Load() != 0 {
+		t.Fatalf("unexpected deliveries: %d", n.sent.Load())
+	}
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/notifiers/slack"
+)
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := &slack.Notifier{NameValue: "slack", URL: server.URL, Timeout: 2 * time.Second}
+	r := notify.NewRetry(n, 3, time.Millisecond, 2*time.Second)
+	var waits []time.Duration
+	r.OnRetry = func(attempt int, wait time.Duration, err error) {
+		var httpErr *notify.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.RetryAfter != time.Second {
+			t.Errorf("expected HTTPError with Retry-After, got %v", err)
+		}
+		waits = append(waits, wait)
+	}
+	if err := r.Send(context.Background(), notify.Event{Service: "svc", Status: "CRIT", OccurredAt: time.Now()}); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls.Load())
+	}
+	if len(waits) != 1 || waits[0] != time.Second {
+		t.Fatalf("expected to wait the full Retry-After, got %v", waits)
+	}
+}
+
+func TestRetryStopsWhenRetryAfterExceedsMaxDelay(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	n := &slack.Notifier{NameValue: "slack", URL: server.URL, Timeout: 2 * time.Second}
+	r := notify.NewRetry(n, 3, time.Millisecond, 20*time.Millisecond)
+	err := r.Send(context.Background(), notify.Event{Service: "svc", Status: "CRIT", OccurredAt: time.Now()})
+	if !notify.Transient(err) {
+		t.Fatalf("expected the transient 429 to be returned, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected no retry before Retry-After, got %d calls", calls.Load())
+	}
+}
+
+func TestRetrySkipsPermanentErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	n := &slack.Notifier{NameValue: "slack", URL: server.URL, Timeout: 2 * time.Second}
+	r := notify.NewRetry(n, 5, time.Millisecond, time.Millisecond)
+	err := r.Send(context.Background(), notify.Event{Service: "svc", Status: "CRIT", OccurredAt: time.Now()})
+	if err == nil || err.Error() != "slack status 400: invalid payload" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single call, got %d", calls.Load())
+	}
+}
+
+func TestRetryGivesUpAfterAttempts(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	n := &slack.Notifier{NameValue: "slack", URL: server.URL, Timeout: 2 * time.Second}
+	r := notify.NewRetry(n, 3, time.Millisecond, 4*time.Millisecond)
+	if err := r.Send(context.Background(), notify.Event{OccurredAt: time.Now()}); err == nil {
+		t.Fatalf("expected error")
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls.Load())
+	}
+}
+
+func TestTransient(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{&notify.HTTPError{StatusCode: 429}, true},
+		{&notify.HTTPError{StatusCode: 503}, true},
+		{&notify.HTTPError{StatusCode: 401}, false},
+		{&textproto.Error{Code: 451, Msg: "try again later"}, true},
+		{&textproto.Error{Code: 550, Msg: "mailbox unavailable"}, false},
+		{errors.New("template: bad"), false},
+	}
+	for _, c := range cases {
+		if got := notify.Transient(c.err); got != c.want {
+			t.Fatalf("Transient(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := notify.ParseRetryAfter("120", now); got != 2*time.Minute {
+		t.Fatalf("unexpected seconds: %v", got)
+	}
+	if got := notify.ParseRetryAfter("Mon, 01 Jan 2024 00:00:30 GMT", now); got != 30*time.Second {
+		t.Fatalf("unexpected date: %v", got)
+	}
+	if got := notify.ParseRetryAfter("soon", now); got != 0 {
+		t.Fatalf("unexpected fallback: %v", got)
+	}
+}
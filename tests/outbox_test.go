@@ -0,0 +1,353 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"services-health-check/internal/app"
+	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/i18n"
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/core/outbox"
+)
+
+// flakyNotifier fails with err until fails reaches zero.
+type flakyNotifier struct {
+	fails atomic.Int32
+	err   error
+	sent  []notify.Event
+}
+
+func (n *flakyNotifier) Name() string { return "flaky" }
+
+func (n *flakyNotifier) Send(ctx context.Context, event notify.Event) error {
+	if n.fails.Add(-1) >= 0 {
+		return n.err
+	}
+	n.sent = append(n.sent, event)
+	return nil
+}
+
+func newOutbox(t *testing.T, n notify.Notifier, maxAttempts int) *outbox.Outbox {
+	t.Helper()
+	dir := t.TempDir()
+	return outbox.New(
+		outbox.NewStore(filepath.Join(dir, "outbox.jsonl")),
+		outbox.NewStore(filepath.Join(dir, "outbox.dead.jsonl")),
+		maxAttempts,
+		map[string]notify.Notifier{"ops": n},
+	)
+}
+
+func TestOutboxStoreRoundTrip(t *testing.T) {
+	store := outbox.NewStore(filepath.Join(t.TempDir(), "outbox.jsonl"))
+	event := notify.Event{
+		Service: "api",
+		Status:  "CRIT",
+		Summary: "down",
+		Items:   []check.Detail{{Label: i18n.Text("URL"), Value: "https://a.test", Kind: check.KindURL}},
+	}
+	if err := store.Append(outbox.NewEntry("ops", event, errors.New("boom"), time.Now())); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	entries, err := store.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Channel != "ops" || entries[0].LastError != "boom" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	item := entries[0].Event.Items[0]
+	if item.Label.String() != "URL" || item.Kind != check.KindURL {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+}
+
+func TestOutboxFlushDelivers(t *testing.T) {
+	n := &flakyNotifier{err: &notify.HTTPError{Channel: "ops", StatusCode: 503}}
+	n.fails.Store(1)
+	box := newOutbox(t, n, 3)
+
+	if err := box.Add("ops", notify.Event{Service: "a"}, n.err); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if err := box.Add("ops", notify.Event{Service: "b"}, n.err); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	// The first re-send fails, so the second entry waits for the next flush.
+	res, err := box.Flush(context.Background())
+	if err != nil || res.Failed != 1 || res.Sent != 0 {
+		t.Fatalf("unexpected first flush: %+v %v", res, err)
+	}
+	res, err = box.Flush(context.Background())
+	if err != nil || res.Sent != 2 {
+		t.Fatalf("unexpected second flush: %+v %v", res, err)
+	}
+	if len(n.sent) != 2 || n.sent[0].Service != "a" || n.sent[1].Service != "b" {
+		t.Fatalf("unexpected delivery order: %+v", n.sent)
+	}
+	if pending, _ := box.Pending.Load(); len(pending) != 0 {
+		t.Fatalf("expected empty outbox, got %+v", pending)
+	}
+}
+
+func TestOutboxDeadLetterAndReplay(t *testing.T) {
+	n := &flakyNotifier{err: &notify.HTTPError{Channel: "ops", StatusCode: 502}}
+	n.fails.Store(2)
+	box := newOutbox(t, n, 2)
+
+	_ = box.Add("ops", notify.Event{Service: "a"}, n.err)
+	_ = box.Add("ops", notify.Event{Service: "b"}, &notify.HTTPError{Channel: "ops", StatusCode: 401})
+
+	res, err := box.Flush(context.Background())
+	if err != nil || res.Dead != 1 {
+		t.Fatalf("unexpected flush: %+v %v", res, err)
+	}
+	dead, _ := box.DeadLetter.Load()
+	if len(dead) != 2 {
+		t.Fatalf("expected permanent and exhausted entries in dead letter, got %+v", dead)
+	}
+
+	res, err = box.Replay(context.Background(), []string{dead[0].ID})
+	if err != nil || res.Sent != 0 || res.Failed != 1 {
+		t.Fatalf("unexpected replay: %+v %v", res, err)
+	}
+	res, err = box.Replay(context.Background(), nil)
+	if err != nil || res.Sent != 2 {
+		t.Fatalf("unexpected replay: %+v %v", res, err)
+	}
+	if dead, _ := box.DeadLetter.Load(); len(dead) != 0 {
+		t.Fatalf("expected empty dead letter, got %+v", dead)
+	}
+	if _, err := box.Replay(context.Background(), []string{"missing"}); err == nil {
+		t.Fatalf("expected unknown id error")
+	}
+}
+
+func TestDeadLetterCommand(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	outboxFile := filepath.Join(t.TempDir(), "outbox.jsonl")
+	path := writeConfig(t, `checks:
+  - type: http
+    name: web
+    url: http://localhost
+channels:
+  - type: slack
+    name: ops
+    url: `+server.URL+`
+notify:
+  outbox_file: `+outboxFile+`
+`)
+	dead := outbox.NewStore(strings.TrimSuffix(outboxFile, ".jsonl") + ".dead.jsonl")
+	entry := outbox.NewEntry("ops", notify.Event{Service: "api", Status: "CRIT", OccurredAt: time.Now()}, errors.New("slack status 404"), time.Now())
+	if err := dead.Append(entry); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := app.DeadLetter(context.Background(), path, []string{"list"}, &out); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if !strings.Contains(out.String(), entry.ID) || !strings.Contains(out.String(), "slack status 404") {
+		t.Fatalf("unexpected list output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := app.DeadLetter(context.Background(), path, []string{"replay", entry.ID}, &out); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if calls.Load() != 1 || !strings.Contains(out.String(), "sent=1") {
+		t.Fatalf("unexpected replay: calls=%d output=%q", calls.Load(), out.String())
+	}
+	if entries, _ := dead.Load(); len(entries) != 0 {
+		t.Fatalf("expected replayed entry to be removed, got %+v", entries)
+	}
+}
+
+func TestOutboxDropsEntrySupersededByLiveEvent(t *testing.T) {
+	n := &flakyNotifier{err: &notify.HTTPError{Channel: "ops", StatusCode: 503}}
+	box := newOutbox(t, n, 3)
+	start := time.Now()
+
+	crit := notify.Event{Service: "api", Status: "CRIT", DedupKey: "healthd/api", OccurredAt: start}
+	other := notify.Event{Service: "db", Status: "CRIT", DedupKey: "healthd/db", OccurredAt: start}
+	_ = box.Add("ops", crit, n.err)
+	_ = box.Add("ops", other, n.err)
+
+	// The live recovery reaches the channel before the outbox is flushed.
+	ok := notify.Event{Service: "api", Status: "OK", DedupKey: "healthd/api", OccurredAt: start.Add(time.Second)}
+	if err := box.Delivered("ops", ok); err != nil {
+		t.Fatalf("delivered: %v", err)
+	}
+	pending, _ := box.Pending.Load()
+	if len(pending) != 1 || pending[0].Event.Service != "db" {
+		t.Fatalf("expected only the unrelated entry to stay, got %+v", pending)
+	}
+
+	// An entry stored later for an older event is skipped by Flush as well.
+	_ = box.Add("ops", crit, n.err)
+	res, err := box.Flush(context.Background())
+	if err != nil || res.Sent != 1 || res.Superseded != 1 {
+		t.Fatalf("unexpected flush: %+v %v", res, err)
+	}
+	if len(n.sent) != 1 || n.sent[0].Service != "db" {
+		t.Fatalf("stale CRIT was re-sent: %+v", n.sent)
+	}
+}
+
+func TestOutboxDeliveredReadsPendingFileOnce(t *testing.T) {
+	n := &flakyNotifier{err: &notify.HTTPError{Channel: "ops", StatusCode: 503}}
+	box := newOutbox(t, n, 3)
+	start := time.Now()
+	_ = box.Add("ops", notify.Event{Service: "api", Status: "CRIT", DedupKey: "healthd/api", OccurredAt: start}, n.err)
+	live := notify.Event{Service: "db", Status: "OK", DedupKey: "healthd/db", OccurredAt: start}
+	if err := box.Delivered("ops", live); err != nil {
+		t.Fatalf("delivered: %v", err)
+	}
+
+	// With nothing to drop, later deliveries must not read the file again.
+	if err := os.WriteFile(box.Pending.Path, []byte("not json\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	live.OccurredAt = start.Add(time.Second)
+	if err := box.Delivered("ops", live); err != nil {
+		t.Fatalf("delivered read the pending file: %v", err)
+	}
+}
+
+func TestRunKeepsOutboxEntryWhenFallbackDelivers(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer target.Close()
+	var primaryCalls atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryCalls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer primary.Close()
+	var backup webhookCapture
+	backupServer := backup.server(t, http.StatusOK)
+
+	dir := t.TempDir()
+	outboxFile := filepath.Join(dir, "outbox.jsonl")
+	path := writeConfig(t, `checks:
+  - type: http
+    name: api
+    url: `+target.URL+`
+    interval: 50ms
+policies:
+  - name: default
+    notify_on_recovery: true
+channels:
+  - type: webhook
+    name: primary
+    url: `+primary.URL+`
+    fallback: backup
+  - type: webhook
+    name: backup
+    url: `+backupServer.URL+`
+routes:
+  - match:
+      name: api
+    to: [primary]
+notify:
+  retry_attempts: 1
+  breaker_failures: 2
+  outbox_file: `+outboxFile+`
+  outbox_interval: 1h
+log:
+  level: error
+`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- app.Run(ctx, path) }()
+
+	// The CRIT fails on primary and is stored; the recovery opens the
+	// circuit and goes to the backup instead.
+	for primaryCalls.Load() == 0 && ctx.Err() == nil {
+		time.Sleep(10 * time.Millisecond)
+	}
+	failing.Store(false)
+	for ctx.Err() == nil {
+		if _, ok := backup.find("api"); ok {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if _, ok := backup.find("api"); !ok {
+		t.Fatalf("expected the recovery on the backup, got %+v", backup.events)
+	}
+
+	pending, err := outbox.NewStore(outboxFile).Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(pending) != 1 || pending[0].Channel != "primary" || pending[0].Event.Status != "CRIT" {
+		t.Fatalf("the primary entry should stay pending after the fallback delivered, got %+v", pending)
+	}
+}
+
+func TestOutboxStoreLocksAcrossStores(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.dead.jsonl")
+	// Two stores on one file stand in for the daemon and the deadletter command.
+	daemon, replay := outbox.NewStore(path), outbox.NewStore(path)
+	if err := daemon.Append(outbox.NewEntry("ops", notify.Event{Service: "old"}, nil, time.Now())); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	appended := make(chan error, 1)
+	err := replay.Update(func(entries []outbox.Entry) []outbox.Entry {
+		go func() {
+			appended <- daemon.Append(outbox.NewEntry("ops", notify.Event{Service: "new"}, nil, time.Now()))
+		}()
+		select {
+		case <-appended:
+			t.Errorf("append ran while the file was being rewritten")
+		case <-time.After(50 * time.Millisecond):
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	select {
+	case err := <-appended:
+		if err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("append still blocked after the update")
+	}
+
+	entries, _ := replay.Load()
+	if len(entries) != 1 || entries[0].Event.Service != "new" {
+		t.Fatalf("expected the concurrent append to survive, got %+v", entries)
+	}
+}
@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"services-health-check/internal/app"
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/notifiers/pagerduty"
+)
+
+type pagerDutyPayload struct {
+	RoutingKey  string `json:"routing_key"`
+	EventAction string `json:"event_action"`
+	DedupKey    string `json:"dedup_key"`
+	Payload     *struct {
+		Severity string `json:"severity"`
+		Source   string `json:"source"`
+	} `json:"payload"`
+}
+
+func TestPagerDutyTriggerAndResolve(t *testing.T) {
+	var got []pagerDutyPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p pagerDutyPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		got = append(got, p)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	n := &pagerduty.Notifier{NameValue: "pd", URL: server.URL, RoutingKey: "key", Timeout: 2 * time.Second}
+	crit := notify.Event{Service: "svc", Status: "CRIT", Summary: "down", OccurredAt: time.Now()}
+	if err := n.Send(context.Background(), crit); err != nil {
+		t.Fatalf("send trigger: %v", err)
+	}
+	ok := notify.Event{Service: "svc", Status: "OK", Summary: "up", OccurredAt: time.Now()}
+	if err := n.Send(context.Background(), ok); err != nil {
+		t.Fatalf("send resolve: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("unexpected requests: %d", len(got))
+	}
+	if got[0].EventAction != "trigger" || got[0].Payload == nil || got[0].Payload.Severity != "critical" {
+		t.Fatalf("unexpected trigger: %+v", got[0])
+	}
+	if got[1].EventAction != "resolve" || got[1].DedupKey != got[0].DedupKey || got[0].DedupKey == "" {
+		t.Fatalf("unexpected resolve: %+v", got[1])
+	}
+}
+
+func TestPagerDutyRequiresRoutingKey(t *testing.T) {
+	n := &pagerduty.Notifier{NameValue: "pd", URL: "http://127.0.0.1:0"}
+	if err := n.Send(context.Background(), notify.Event{Service: "svc", Status: "CRIT"}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestPagerDutySkipsFlapEvents(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	n := &pagerduty.Notifier{NameValue: "pd", URL: server.URL, RoutingKey: "key", Timeout: 2 * time.Second}
+	event := notify.Event{Service: "svc", Status: "OK", DedupKey: "healthd/svc", Labels: map[string]string{"flapping": "stopped"}}
+	if err := n.Send(context.Background(), event); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("flap events should not reach PagerDuty, got %d requests", calls)
+	}
+}
+
+func TestRunSendsPerCheckEventsToPagerDutyWhenAggregating(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer target.Close()
+	var mu sync.Mutex
+	var pd []pagerDutyPayload
+	pdServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p pagerDutyPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err == nil {
+			mu.Lock()
+			pd = append(pd, p)
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer pdServer.Close()
+	var chat webhookCapture
+	chatServer := chat.server(t, http.StatusOK)
+
+	path := writeConfig(t, `checks:
+  - type: http
+    name: api
+    url: `+target.URL+`
+    interval: 1h
+  - type: http
+    name: web
+    url: `+target.URL+`
+    interval: 1h
+channels:
+  - type: pagerduty
+    name: pd
+    url: `+pdServer.URL+`
+    pagerduty_routing_key: key
+  - type: webhook
+    name: chat
+    url: `+chatServer.URL+`
+routes:
+  - match:
+      type: http
+    to: [pd, chat]
+notify:
+  aggregate_by_type: true
+  aggregate_window: 1h
+log:
+  level: error
+`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- app.Run(ctx, path) }()
+
+	delivered := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		_, aggregated := chat.find("http")
+		return len(pd) >= 2 && aggregated
+	}
+	for !delivered() && ctx.Err() == nil {
+		time.Sleep(20 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !delivered() {
+		t.Fatalf("expected two PagerDuty events and one aggregate; pd=%+v chat=%+v", pd, chat.events)
+	}
+	keys := map[string]bool{}
+	for _, p := range pd {
+		keys[p.DedupKey] = true
+	}
+	if len(pd) != 2 || !keys["healthd/api"] || !keys["healthd/web"] {
+		t.Fatalf("expected one trigger per check, got %+v", pd)
+	}
+	if _, ok := chat.find("api"); ok {
+		t.Fatalf("the chat channel should only get the aggregate")
+	}
+}
@@ -0,0 +1,272 @@
+package tests
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"services-health-check/internal/core/check"
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/core/policy"
+)
+
+func evaluate(t *testing.T, p policy.Policy, name string, status check.Status) string {
+	t.Helper()
+	ev, err := p.Evaluate(context.Background(), check.Result{Name: name, Status: status})
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if ev == nil {
+		return ""
+	}
+	return ev.PreviousStatus + "->" + ev.Status
+}
+
+func TestSimplePolicyTransitionsOnly(t *testing.T) {
+	p := policy.NewSimplePolicy(0, true)
+	steps := []struct {
+		status check.Status
+		want   string
+	}{
+		{check.StatusOK, ""},
+		{check.StatusOK, ""},
+		{check.StatusWarn, "OK->WARN"},
+		{check.StatusWarn, ""},
+		{check.StatusCrit, "WARN->CRIT"},
+		{check.StatusOK, "CRIT->OK"},
+		{check.StatusOK, ""},
+	}
+	for i, step := range steps {
+		if got := evaluate(t, p, "svc", step.status); got != step.want {
+			t.Fatalf("step %d: got %q want %q", i, got, step.want)
+		}
+	}
+}
+
+func TestSimplePolicyRecoveryDisabled(t *testing.T) {
+	p := policy.NewSimplePolicy(0, false)
+	if got := evaluate(t, p, "svc", check.StatusCrit); got != "OK->CRIT" {
+		t.Fatalf("unexpected event: %q", got)
+	}
+	if got := evaluate(t, p, "svc", check.StatusOK); got != "" {
+		t.Fatalf("unexpected recovery event: %q", got)
+	}
+	if got := evaluate(t, p, "svc", check.StatusCrit); got != "OK->CRIT" {
+		t.Fatalf("unexpected event after silent recovery: %q", got)
+	}
+}
+
+func TestSimplePolicyThresholds(t *testing.T) {
+	p := policy.NewSimplePolicy(0, true)
+	p.FailureThreshold = 3
+	p.SuccessThreshold = 2
+	steps := []struct {
+		status check.Status
+		want   string
+	}{
+		{check.StatusCrit, ""},
+		{check.StatusOK, ""},
+		{check.StatusCrit, ""},
+		{check.StatusCrit, ""},
+		{check.StatusCrit, "OK->CRIT"},
+		{check.StatusWarn, "CRIT->WARN"},
+		{check.StatusOK, ""},
+		{check.StatusOK, "WARN->OK"},
+	}
+	for i, step := range steps {
+		if got := evaluate(t, p, "svc", step.status); got != step.want {
+			t.Fatalf("step %d: got %q want %q", i, got, step.want)
+		}
+	}
+}
+
+func TestSimplePolicyFlapDetection(t *testing.T) {
+	p := policy.NewSimplePolicy(0, true)
+	p.Flap = policy.NewFlapDetector(4, 50, 25)
+
+	var flapEvents []string
+	record := func(status check.Status) string {
+		ev, err := p.Evaluate(context.Background(), check.Result{Name: "svc", Status: status})
+		if err != nil {
+			t.Fatalf("evaluate: %v", err)
+		}
+		if ev == nil {
+			return ""
+		}
+		if f := ev.Labels["flapping"]; f != "" {
+			flapEvents = append(flapEvents, f)
+		}
+		return ev.Status
+	}
+
+	for _, status := range []check.Status{check.StatusWarn, check.StatusOK, check.StatusWarn, check.StatusOK, check.StatusWarn, check.StatusOK} {
+		record(status)
+	}
+	if len(flapEvents) != 1 || flapEvents[0] != "started" {
+		t.Fatalf("expected flapping to start once, got %v", flapEvents)
+	}
+
+	for i := 0; i < 4; i++ {
+		record(check.StatusOK)
+	}
+	if len(flapEvents) != 2 || flapEvents[1] != "stopped" {
+		t.Fatalf("expected flapping to stop, got %v", flapEvents)
+	}
+
+	if got := record(check.StatusCrit); got != "CRIT" {
+		t.Fatalf("expected normal alert after flapping, got %q", got)
+	}
+}
+
+func TestSimplePolicyStateSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	first := policy.NewSimplePolicy(time.Hour, true)
+	first.Store = policy.NewFileStore(path)
+	if err := first.Restore(); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if got := evaluate(t, first, "svc", check.StatusCrit); got != "OK->CRIT" {
+		t.Fatalf("unexpected event: %q", got)
+	}
+
+	second := policy.NewSimplePolicy(time.Hour, true)
+	second.Store = policy.NewFileStore(path)
+	if err := second.Restore(); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if got := evaluate(t, second, "svc", check.StatusCrit); got != "" {
+		t.Fatalf("expected no re-alert after restart, got %q", got)
+	}
+}
+
+func TestDependencyPolicySuppressesDependents(t *testing.T) {
+	p := policy.NewDependencyPolicy(policy.NewSimplePolicy(0, true), map[string][]string{
+		"pods-a": {"cluster"},
+		"pods-b": {"cluster"},
+	})
+
+	if got := evaluate(t, p, "pods-a", check.StatusOK); got != "" {
+		t.Fatalf("unexpected event: %q", got)
+	}
+	if got := evaluate(t, p, "cluster", check.StatusCrit); got != "OK->CRIT" {
+		t.Fatalf("expected parent alert, got %q", got)
+	}
+	// Each newly suppressed dependent updates the parent's open incident.
+	ev, _ := p.Evaluate(context.Background(), check.Result{Name: "pods-a", Status: check.StatusCrit})
+	if ev == nil || ev.Service != "cluster" || ev.Status != "CRIT" || ev.Labels["suppressed_dependents"] != "pods-a" {
+		t.Fatalf("expected parent update listing pods-a, got %+v", ev)
+	}
+	ev, _ = p.Evaluate(context.Background(), check.Result{Name: "pods-b", Status: check.StatusCrit})
+	if ev == nil || ev.Service != "cluster" || ev.Labels["suppressed_dependents"] != "pods-a,pods-b" {
+		t.Fatalf("expected parent update listing both dependents, got %+v", ev)
+	}
+	if got := evaluate(t, p, "pods-a", check.StatusCrit); got != "" {
+		t.Fatalf("expected dependent to stay suppressed without a new update, got %q", got)
+	}
+
+	if got := evaluate(t, p, "cluster", check.StatusOK); got != "CRIT->OK" {
+		t.Fatalf("expected parent recovery, got %q", got)
+	}
+	if got := evaluate(t, p, "pods-a", check.StatusCrit); got != "OK->CRIT" {
+		t.Fatalf("expected dependent alert after parent recovered, got %q", got)
+	}
+}
+
+func TestDependencyPolicyUsesNotifiedParentState(t *testing.T) {
+	parent := policy.NewSimplePolicy(0, true)
+	parent.FailureThreshold = 2
+	p := policy.NewDependencyPolicy(parent, map[string][]string{"pods": {"cluster"}})
+
+	// The parent's first CRIT is below its threshold and was not announced,
+	// so the dependent, sharing the threshold, still alerts on its own.
+	if got := evaluate(t, p, "cluster", check.StatusCrit); got != "" {
+		t.Fatalf("expected parent below threshold, got %q", got)
+	}
+	evaluate(t, p, "pods", check.StatusCrit)
+	if got := evaluate(t, p, "pods", check.StatusCrit); got != "OK->CRIT" {
+		t.Fatalf("expected dependent alert while parent is not notified, got %q", got)
+	}
+}
+
+func TestSimplePolicyReminders(t *testing.T) {
+	p := policy.NewSimplePolicy(0, true)
+	p.RepeatInterval = 20 * time.Millisecond
+	p.MaxRepeats = 1
+
+	if got := evaluate(t, p, "svc", check.StatusCrit); got != "OK->CRIT" {
+		t.Fatalf("unexpected event: %q", got)
+	}
+	if got := evaluate(t, p, "svc", check.StatusCrit); got != "" {
+		t.Fatalf("unexpected early reminder: %q", got)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	ev, err := p.Evaluate(context.Background(), check.Result{Name: "svc", Status: check.StatusCrit})
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if ev == nil || ev.Labels["reminder"] != "1" || !strings.Contains(ev.Summary, "已持續") {
+		t.Fatalf("expected reminder event, got %+v", ev)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if got := evaluate(t, p, "svc", check.StatusCrit); got != "" {
+		t.Fatalf("expected reminders to stop after max_repeats, got %q", got)
+	}
+}
+
+func TestFormatElapsed(t *testing.T) {
+	if got := policy.FormatElapsed(3*time.Hour + 12*time.Minute + 40*time.Second); got != "3h12m" {
+		t.Fatalf("unexpected format: %q", got)
+	}
+}
+
+func TestSimplePolicyIncidentLifecycle(t *testing.T) {
+	p := policy.NewSimplePolicy(0, true)
+	next := func(status check.Status) *notify.Event {
+		ev, err := p.Evaluate(context.Background(), check.Result{Name: "svc", Status: status})
+		if err != nil {
+			t.Fatalf("evaluate: %v", err)
+		}
+		if ev == nil {
+			t.Fatalf("expected event for %s", status)
+		}
+		return ev
+	}
+
+	opened := next(check.StatusWarn)
+	if opened.IncidentID == "" || opened.StartedAt.IsZero() || opened.DedupKey != policy.DedupKey("svc") {
+		t.Fatalf("expected incident to open: %+v", opened)
+	}
+	escalated := next(check.StatusCrit)
+	if escalated.IncidentID != opened.IncidentID {
+		t.Fatalf("incident changed within failure: %q vs %q", escalated.IncidentID, opened.IncidentID)
+	}
+	resolved := next(check.StatusOK)
+	if resolved.IncidentID != opened.IncidentID || resolved.ResolvedAt.IsZero() {
+		t.Fatalf("expected incident to resolve: %+v", resolved)
+	}
+	reopened := next(check.StatusCrit)
+	if reopened.IncidentID == opened.IncidentID {
+		t.Fatalf("expected a new incident after recovery")
+	}
+}
+
+func TestSimplePolicyCopiesCheckLabels(t *testing.T) {
+	p := policy.NewSimplePolicy(0, true)
+	labels := map[string]string{"team": "platform", "env": "prod"}
+	ev, err := p.Evaluate(context.Background(), check.Result{Name: "api", Status: check.StatusCrit, Labels: labels})
+	if err != nil || ev == nil {
+		t.Fatalf("expected event, got %v %v", ev, err)
+	}
+	if ev.Labels["team"] != "platform" || ev.Labels["env"] != "prod" || ev.Labels["status"] != "CRIT" {
+		t.Fatalf("unexpected labels: %v", ev.Labels)
+	}
+	ev.Labels["team"] = "data"
+	if labels["team"] != "platform" {
+		t.Fatalf("event labels share the check's map")
+	}
+}
@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/core/route"
+)
+
+func mustMatcher(t *testing.T, name, nameRegex, typ, status, minStatus string, labels map[string]string) route.Matcher {
+	t.Helper()
+	m, err := route.NewMatcher(name, nameRegex, typ, status, minStatus, labels)
+	if err != nil {
+		t.Fatalf("new matcher: %v", err)
+	}
+	return m
+}
+
+func TestRouteMatcher(t *testing.T) {
+	event := notify.Event{Service: "api-eu-1", Type: "http", Status: "WARN", Labels: map[string]string{"team": "platform"}}
+	cases := []struct {
+		name  string
+		match route.Matcher
+		want  bool
+	}{
+		{"empty", route.Matcher{}, true},
+		{"exact name", mustMatcher(t, "api-eu-1", "", "", "", "", nil), true},
+		{"other name", mustMatcher(t, "api", "", "", "", "", nil), false},
+		{"glob", mustMatcher(t, "api-*", "", "", "", "", nil), true},
+		{"glob miss", mustMatcher(t, "db-*", "", "", "", "", nil), false},
+		{"regex", mustMatcher(t, "", `api-(eu|us)-\d+`, "", "", "", nil), true},
+		{"regex is anchored", mustMatcher(t, "", "eu", "", "", "", nil), false},
+		{"type", mustMatcher(t, "", "", "http", "", "", nil), true},
+		{"other type", mustMatcher(t, "", "", "ssl", "", "", nil), false},
+		{"status", mustMatcher(t, "", "", "", "warn", "", nil), true},
+		{"min status below", mustMatcher(t, "", "", "", "", "WARN", nil), true},
+		{"min status above", mustMatcher(t, "", "", "", "", "CRIT", nil), false},
+		{"labels", mustMatcher(t, "", "", "", "", "", map[string]string{"team": "platform"}), true},
+		{"other label", mustMatcher(t, "", "", "", "", "", map[string]string{"team": "data"}), false},
+	}
+	for _, tc := range cases {
+		if got := tc.match.Matches(event); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	if _, err := route.NewMatcher("", "api-(", "", "", "", nil); err == nil {
+		t.Fatalf("expected invalid regex error")
+	}
+	if _, err := route.NewMatcher("api-[", "", "", "", "", nil); err == nil {
+		t.Fatalf("expected invalid glob error")
+	}
+}
+
+func TestRouteSelect(t *testing.T) {
+	routes := []route.Route{
+		{Match: mustMatcher(t, "", "", "", "CRIT", "", nil), To: []string{"pager"}, Continue: false},
+		{Match: mustMatcher(t, "", "", "", "", "WARN", nil), To: []string{"slack"}, Continue: true},
+		{Match: mustMatcher(t, "api-*", "", "", "", "", nil), To: []string{"api-team"}, Continue: true},
+		{Default: true, To: []string{"discord"}},
+	}
+	cases := []struct {
+		event notify.Event
+		want  []int
+	}{
+		{notify.Event{Service: "api-1", Status: "CRIT"}, []int{0}},
+		{notify.Event{Service: "api-1", Status: "WARN"}, []int{1, 2}},
+		{notify.Event{Service: "db", Status: "WARN"}, []int{1}},
+		{notify.Event{Service: "db", Status: "OK"}, []int{3}},
+		{notify.Event{Service: "db", Status: "OK", PreviousStatus: "WARN"}, []int{1}},
+		{notify.Event{Service: "db", Status: "OK", PreviousStatus: "UNKNOWN"}, []int{3}},
+	}
+	for _, tc := range cases {
+		if got := route.Select(routes, tc.event); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s %s: got %v, want %v", tc.event.Service, tc.event.Status, got, tc.want)
+		}
+	}
+	if got := route.Select(routes[:3], notify.Event{Service: "db", Status: "OK"}); got != nil {
+		t.Errorf("expected no routes without a default, got %v", got)
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"services-health-check/internal/app"
+	"services-health-check/internal/core/scheduler"
 )
 
 func TestAppRunWithInterval(t *testing.T) {
@@ -57,3 +59,109 @@ log:
 		t.Fatalf("timeout waiting for app run")
 	}
 }
+
+func TestPoolLimitsConcurrency(t *testing.T) {
+	pool := scheduler.NewPool(2)
+	var current, peak, runs int32
+	for i := 0; i < 6; i++ {
+		err := pool.Add(scheduler.Job{
+			Name:    fmt.Sprintf("job-%d", i),
+			RunOnce: true,
+			Run: func(ctx context.Context) bool {
+				n := atomic.AddInt32(&current, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				atomic.AddInt32(&current, -1)
+				atomic.AddInt32(&runs, 1)
+				return true
+			},
+		})
+		if err != nil {
+			t.Fatalf("add job: %v", err)
+		}
+	}
+
+	if err := pool.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	select {
+	case <-pool.Done():
+	case <-time.After(1 * time.Second):
+		t.Fatalf("timeout waiting for jobs")
+	}
+	if runs != 6 {
+		t.Fatalf("unexpected runs: %d", runs)
+	}
+	if peak > 2 {
+		t.Fatalf("concurrency limit exceeded: %d", peak)
+	}
+}
+
+func TestPoolStopOnFalse(t *testing.T) {
+	pool := scheduler.NewPool(1)
+	var runs int32
+	err := pool.Add(scheduler.Job{
+		Name:     "flaky",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) bool {
+			return atomic.AddInt32(&runs, 1) < 3
+		},
+	})
+	if err != nil {
+		t.Fatalf("add job: %v", err)
+	}
+
+	if err := pool.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	select {
+	case <-pool.Done():
+	case <-time.After(1 * time.Second):
+		t.Fatalf("timeout waiting for job removal")
+	}
+	if err := pool.Stop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if got := atomic.LoadInt32(&runs); got != 3 {
+		t.Fatalf("unexpected runs: %d", got)
+	}
+}
+
+func TestPoolDoneAfterStopDuringFirstRun(t *testing.T) {
+	pool := scheduler.NewPool(1)
+	err := pool.Add(scheduler.Job{
+		Name:     "stopper",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) bool {
+			// Stop while this run is in flight, so the job is not scheduled.
+			stopCtx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			_ = pool.Stop(stopCtx)
+			return true
+		},
+	})
+	if err != nil {
+		t.Fatalf("add job: %v", err)
+	}
+	if err := pool.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	select {
+	case <-pool.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("Done not closed after the job was dropped by Stop")
+	}
+}
+
+func TestPoolRejectsInvalidSchedule(t *testing.T) {
+	pool := scheduler.NewPool(1)
+	err := pool.Add(scheduler.Job{Name: "bad", Schedule: "not a cron", Run: func(ctx context.Context) bool { return true }})
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/core/silence"
+)
+
+func TestSilenceOneOffWindow(t *testing.T) {
+	s, err := silence.New("db-migration", silence.Matcher{Name: "db"}, "2026-01-10T01:00:00Z", "2026-01-10T03:00:00Z", "", 0)
+	if err != nil {
+		t.Fatalf("new silence: %v", err)
+	}
+	event := notify.Event{Service: "db", Status: "CRIT"}
+
+	if !s.Silences(event, time.Date(2026, 1, 10, 2, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected event to be silenced inside the window")
+	}
+	if s.Silences(event, time.Date(2026, 1, 10, 3, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected window to be closed at end")
+	}
+	if s.Silences(notify.Event{Service: "web", Status: "CRIT"}, time.Date(2026, 1, 10, 2, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected other checks to pass")
+	}
+}
+
+func TestSilenceRecurringWindow(t *testing.T) {
+	match := silence.Matcher{Type: "k8s_pods", Labels: map[string]string{"status": "WARN"}}
+	s, err := silence.New("nightly", match, "", "", "0 2 * * *", 30*time.Minute)
+	if err != nil {
+		t.Fatalf("new silence: %v", err)
+	}
+	event := notify.Event{Service: "pods", Type: "k8s_pods", Status: "WARN", Labels: map[string]string{"status": "WARN"}}
+
+	if !s.Silences(event, time.Date(2026, 1, 10, 2, 15, 0, 0, time.Local)) {
+		t.Fatalf("expected event to be silenced inside the recurring window")
+	}
+	if s.Silences(event, time.Date(2026, 1, 10, 2, 45, 0, 0, time.Local)) {
+		t.Fatalf("expected recurring window to be closed")
+	}
+}
+
+func TestSilenceRequiresWindow(t *testing.T) {
+	if _, err := silence.New("empty", silence.Matcher{}, "", "", "", 0); err == nil {
+		t.Fatalf("expected error")
+	}
+	if _, err := silence.New("no-duration", silence.Matcher{}, "", "", "0 2 * * *", 0); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestHeldAnnouncesFailureAfterWindow(t *testing.T) {
+	s, err := silence.New("maintenance", silence.Matcher{Name: "db"}, "2026-01-10T01:00:00Z", "2026-01-10T03:00:00Z", "", 0)
+	if err != nil {
+		t.Fatalf("new silence: %v", err)
+	}
+	held := silence.NewHeld()
+	silencedAt := func(now time.Time) func(notify.Event) bool {
+		return func(ev notify.Event) bool { return s.Silences(ev, now) }
+	}
+
+	// The check goes CRIT during the window and stays CRIT after it.
+	held.Add(notify.Event{Service: "db", Status: "CRIT", PreviousStatus: "OK"})
+	if got := held.Expired(silencedAt(time.Date(2026, 1, 10, 2, 0, 0, 0, time.UTC))); len(got) != 0 {
+		t.Fatalf("expected nothing while the window is open, got %+v", got)
+	}
+	got := held.Expired(silencedAt(time.Date(2026, 1, 10, 3, 0, 0, 0, time.UTC)))
+	if len(got) != 1 || got[0].Status != "CRIT" {
+		t.Fatalf("expected the CRIT to be announced after the window, got %+v", got)
+	}
+	if got := held.Expired(silencedAt(time.Date(2026, 1, 10, 4, 0, 0, 0, time.UTC))); len(got) != 0 {
+		t.Fatalf("expected a single announcement, got %+v", got)
+	}
+	if held.Sent(notify.Event{Service: "db", Status: "OK", PreviousStatus: "CRIT"}) {
+		t.Fatalf("expected the recovery of an announced failure to be sent")
+	}
+}
+
+func TestHeldDropsRecoveryOfUnannouncedFailure(t *testing.T) {
+	held := silence.NewHeld()
+	held.Add(notify.Event{Service: "db", Status: "CRIT", PreviousStatus: "OK"})
+	if !held.Sent(notify.Event{Service: "db", Status: "OK", PreviousStatus: "CRIT"}) {
+		t.Fatalf("expected recovery of a silenced failure to be dropped")
+	}
+
+	// A failure recovered within the window is not announced at all.
+	held.Add(notify.Event{Service: "web", Status: "CRIT", PreviousStatus: "OK"})
+	held.Add(notify.Event{Service: "web", Status: "OK", PreviousStatus: "CRIT"})
+	if got := held.Expired(func(notify.Event) bool { return false }); len(got) != 0 {
+		t.Fatalf("expected nothing to announce, got %+v", got)
+	}
+}
@@ -0,0 +1,39 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/notifiers/smtp"
+)
+
+func TestSMTPThreadHeaders(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	root := "<inc-1@healthd>"
+	base := notify.Event{Service: "svc", IncidentID: "inc-1", StartedAt: start}
+
+	opening := base
+	opening.Status, opening.PreviousStatus, opening.OccurredAt = "CRIT", "OK", start
+	headers := strings.Join(smtp.ThreadHeaders(opening), "\n")
+	if !strings.Contains(headers, "Message-ID: "+root) || strings.Contains(headers, "In-Reply-To") || strings.Contains(headers, "References") {
+		t.Fatalf("the opening email should be the thread root:\n%s", headers)
+	}
+
+	escalated := opening
+	escalated.Labels = map[string]string{"escalation": "15m0s"}
+	escalated.OccurredAt = start.Add(15 * time.Minute)
+	recovery := base
+	recovery.Status, recovery.PreviousStatus, recovery.OccurredAt = "OK", "CRIT", start.Add(time.Hour)
+	for _, event := range []notify.Event{escalated, recovery} {
+		headers := strings.Join(smtp.ThreadHeaders(event), "\n")
+		if strings.Contains(headers, "Message-ID: "+root) || !strings.Contains(headers, "In-Reply-To: "+root) || !strings.Contains(headers, "References: "+root) {
+			t.Fatalf("a later email should reply to the root:\n%s", headers)
+		}
+	}
+
+	if headers := smtp.ThreadHeaders(notify.Event{Service: "svc", Status: "CRIT"}); headers != nil {
+		t.Fatalf("expected no thread headers without an incident, got %q", headers)
+	}
+}
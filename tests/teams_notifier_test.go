@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/notifiers/format"
+	"services-health-check/internal/notifiers/teams"
+)
+
+type teamsPayload struct {
+	Type        string `json:"type"`
+	Attachments []struct {
+		ContentType string `json:"contentType"`
+		Content     struct {
+			Type string `json:"type"`
+			Body []struct {
+				Type  string  `json:"type"`
+				Style string  `json:"style"`
+				Text  *string `json:"text"`
+				Facts []struct {
+					Title string `json:"title"`
+					Value string `json:"value"`
+				} `json:"facts"`
+			} `json:"body"`
+		} `json:"content"`
+	} `json:"attachments"`
+}
+
+func TestTeamsAdaptiveCard(t *testing.T) {
+	var got teamsPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := &teams.Notifier{NameValue: "teams", URL: server.URL, Timeout: 2 * time.Second}
+	event := notify.Event{Service: "svc", Status: "CRIT", Summary: "sum", Details: "a; b", OccurredAt: time.Now()}
+	if err := n.Send(context.Background(), event); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if len(got.Attachments) != 1 || got.Attachments[0].Content.Type != "AdaptiveCard" {
+		t.Fatalf("missing adaptive card: %+v", got)
+	}
+	body := got.Attachments[0].Content.Body
+	if len(body) == 0 || body[0].Style != "attention" {
+		t.Fatalf("unexpected colour band: %+v", body)
+	}
+	var facts int
+	for _, el := range body {
+		if el.Type == "FactSet" {
+			facts = len(el.Facts)
+		}
+	}
+	if facts < 3 {
+		t.Fatalf("missing facts: %+v", body)
+	}
+}
+
+func TestTeamsCardOmitsEmptyTextBlocks(t *testing.T) {
+	var got teamsPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := &teams.Notifier{NameValue: "teams", URL: server.URL, Timeout: 2 * time.Second}
+	event := notify.Event{Service: "svc", Status: "OK", Summary: "fine", OccurredAt: time.Now()}
+	if err := n.Send(context.Background(), event); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	var blocks int
+	for _, el := range got.Attachments[0].Content.Body {
+		if el.Type != "TextBlock" {
+			continue
+		}
+		blocks++
+		if el.Text == nil || *el.Text == "" {
+			t.Fatalf("TextBlock without text: %+v", got.Attachments[0].Content.Body)
+		}
+	}
+	if blocks != 1 {
+		t.Fatalf("expected only the summary block, got %d", blocks)
+	}
+
+	tmpls, err := format.ParseTemplates("teams", "", `{{ if .Details }}{{ .Details }}{{ end }}`)
+	if err != nil {
+		t.Fatalf("parse templates: %v", err)
+	}
+	n.Templates = tmpls
+	if err := n.Send(context.Background(), event); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	for _, el := range got.Attachments[0].Content.Body {
+		if el.Type == "TextBlock" {
+			t.Fatalf("an empty body template should leave no TextBlock: %+v", got.Attachments[0].Content.Body)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"services-health-check/internal/core/i18n"
+	"services-health-check/internal/core/notify"
+	"services-health-check/internal/notifiers/telegram"
+)
+
+type telegramPayload struct {
+	ChatID    string `json:"chat_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode"`
+}
+
+func TestTelegramSendMessage(t *testing.T) {
+	var got telegramPayload
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	n := &telegram.Notifier{
+		NameValue: "tg",
+		URL:       server.URL,
+		BotToken:  "123:abc",
+		ChatID:    "-100",
+		ParseMode: "MarkdownV2",
+		Timeout:   2 * time.Second,
+	}
+	event := notify.Event{Service: "svc", Status: "CRIT", Summary: "down", Details: "a; b", OccurredAt: time.Now()}
+	if err := n.Send(context.Background(), event); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if path != "/bot123:abc/sendMessage" {
+		t.Fatalf("unexpected path: %s", path)
+	}
+	if got.ChatID != "-100" || got.ParseMode != "MarkdownV2" {
+		t.Fatalf("unexpected payload: %+v", got)
+	}
+}
+
+func TestTelegramMarkdownV2Escaping(t *testing.T) {
+	event := notify.Event{
+		Service: "k8s-prod",
+		Status:  "WARN",
+		Summary: "1 pod not ready (ns=default)",
+		Details: "例: default/api-7f9c-x2",
+	}
+	text := telegram.BuildText(event, "MarkdownV2")
+	if !strings.Contains(text, "`default/api-7f9c-x2`") {
+		t.Fatalf("code span should keep its content unescaped: %q", text)
+	}
+	if !strings.Contains(text, `\(ns\=default\)`) || !strings.Contains(text, `\[WARN\] k8s\-prod`) {
+		t.Fatalf("special characters should be escaped: %q", text)
+	}
+}
+
+func TestTelegramHTMLEscaping(t *testing.T) {
+	event := notify.Event{Service: "svc", Status: "CRIT", Summary: "a < b", Details: "example.com & more"}
+	text := telegram.BuildText(event, "HTML")
+	if !strings.Contains(text, "<code>example.com</code>") || !strings.Contains(text, "a &lt; b") || !strings.Contains(text, "&amp; more") {
+		t.Fatalf("unexpected html: %q", text)
+	}
+}
+
+func TestTelegramTruncatesEscapedText(t *testing.T) {
+	// Escaped as HTML, each short item grows to several times its length.
+	var lines []string
+	for i := 0; i < 1000; i++ {
+		lines = append(lines, "a&b")
+	}
+	event := notify.Event{Service: "svc", Status: "CRIT", Summary: "down", Details: strings.Join(lines, "; ")}
+	for _, mode := range []string{"MarkdownV2", "HTML", ""} {
+		text := telegram.BuildText(event, mode)
+		if n := len([]rune(text)); n > 4096 {
+			t.Fatalf("%q: text has %d characters, over the 4096 limit", mode, n)
+		}
+		if !strings.Contains(text, i18n.T("", "label.truncated")) {
+			t.Fatalf("%q: expected a truncation marker: %q", mode, text)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package tests
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -10,11 +11,14 @@ import (
 	"time"
 
 	"services-health-check/internal/core/notify"
+	"services-health-check/internal/notifiers/format"
 	"services-health-check/internal/notifiers/webhook"
 )
 
 type webhookPayload struct {
-	Details string `json:"details"`
+	Details    string `json:"details"`
+	IncidentID string `json:"incidentid"`
+	DedupKey   string `json:"dedupkey"`
 }
 
 func TestWebhookPayload(t *testing.T) {
@@ -29,11 +33,66 @@ func TestWebhookPayload(t *testing.T) {
 	defer server.Close()
 
 	n := &webhook.Notifier{NameValue: "webhook", URL: server.URL, Timeout: 2 * time.Second}
-	event := notify.Event{Service: "svc", Status: "WARN", Summary: "sum", Details: "a; b", OccurredAt: time.Now()}
+	event := notify.Event{Service: "svc", Status: "WARN", Summary: "sum", Details: "a; b", IncidentID: "inc-1", DedupKey: "healthd/svc", OccurredAt: time.Now()}
 	if err := n.Send(context.Background(), event); err != nil {
 		t.Fatalf("send error: %v", err)
 	}
 	if !strings.Contains(got.Details, "- a") {
 		t.Fatalf("expected list details, got: %q", got.Details)
 	}
+	if got.IncidentID != "inc-1" || got.DedupKey != "healthd/svc" {
+		t.Fatalf("missing incident fields: %+v", got)
+	}
+}
+
+func TestWebhookTemplateHeadersAndSignature(t *testing.T) {
+	var (
+		method string
+		auth   string
+		sig    string
+		body   []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		auth = r.Header.Get("Authorization")
+		sig = r.Header.Get("X-Signature")
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tmpl, err := format.ParseTemplate("ticket", `{"title":{{ json (printf "[%s] %s" .Status .Service) }},"body":{{ json (details .Details) }}}`)
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+	n := &webhook.Notifier{
+		NameValue:  "ticket",
+		URL:        server.URL,
+		Method:     "put",
+		Headers:    map[string]string{"Authorization": "Bearer secret-token"},
+		Template:   tmpl,
+		HMACSecret: "shh",
+		HMACHeader: "X-Signature",
+		Timeout:    2 * time.Second,
+	}
+	event := notify.Event{Service: "svc", Status: "CRIT", Summary: "sum", Details: `a "quoted"; b`, OccurredAt: time.Now()}
+	if err := n.Send(context.Background(), event); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if method != http.MethodPut || auth != "Bearer secret-token" {
+		t.Fatalf("unexpected request: method=%s auth=%q", method, auth)
+	}
+	var got struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("rendered body is not valid json: %v: %s", err, body)
+	}
+	if got.Title != "[CRIT] svc" || !strings.Contains(got.Body, `- a "quoted"`) {
+		t.Fatalf("unexpected rendered body: %+v", got)
+	}
+	if sig != webhook.Sign("shh", body) || !strings.HasPrefix(sig, "sha256=") {
+		t.Fatalf("unexpected signature: %q", sig)
+	}
 }